@@ -0,0 +1,162 @@
+// Package analysis works out what a Game of Life board is made of.
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// Object is one of the objects a board was separated into.
+type Object struct {
+	// Code is the apgcode of the object, the same for all of its phases, rotations and reflections.
+	Code string `json:"code"`
+	// Name is the common name of the object, or "" if it does not have one.
+	Name   string `json:"name,omitempty"`
+	Kind   Kind   `json:"kind"`
+	Period int    `json:"period"`
+	// X and Y are the top left corner of the bounding box of the object on the board, and Population its alive cells.
+	X          int `json:"x"`
+	Y          int `json:"y"`
+	Population int `json:"population"`
+}
+
+// Count is the number of objects with the same apgcode in a census.
+type Count struct {
+	Code   string `json:"code"`
+	Name   string `json:"name,omitempty"`
+	Kind   Kind   `json:"kind"`
+	Period int    `json:"period"`
+	Count  int    `json:"count"`
+}
+
+// Census lists the objects on a board, and how many there are of each.
+type Census struct {
+	Objects []Object `json:"objects"`
+	// Counts are sorted with the most common objects first, then by apgcode.
+	Counts []Count `json:"counts"`
+}
+
+// classified holds the classification of every shape seen so far, by key, shared between censuses.
+var classified sync.Map
+
+// TakeCensus separates the alive cells into objects, where cells up to two apart are in the same object,
+// and classifies each by evolving it on its own. The cells wrap around a board of the given width and height,
+// or are on an unbounded board if both are 0. Objects that have been seen before are only evolved once.
+// Censuses can be taken from several goroutines at once.
+func TakeCensus(cells []util.Cell, width, height int) (*Census, error) {
+	census := &Census{Objects: []Object{}, Counts: []Count{}}
+	counts := make(map[string]*Count)
+	for _, object := range group(cells, width, height) {
+		s, at := normalise(object)
+		var c classification
+		if seen, ok := classified.Load(s.key()); ok {
+			c = seen.(classification)
+		} else {
+			var err error
+			if c, err = classify(object); err != nil {
+				return nil, err
+			}
+			classified.Store(s.key(), c)
+		}
+		name, err := nameOf(c.code)
+		if err != nil {
+			return nil, err
+		}
+
+		at = wrap(at, width, height)
+		census.Objects = append(census.Objects, Object{
+			Code:       c.code,
+			Name:       name,
+			Kind:       c.kind,
+			Period:     c.period,
+			X:          at.X,
+			Y:          at.Y,
+			Population: len(object),
+		})
+		if counts[c.code] == nil {
+			counts[c.code] = &Count{Code: c.code, Name: name, Kind: c.kind, Period: c.period}
+		}
+		counts[c.code].Count++
+	}
+
+	for _, count := range counts {
+		census.Counts = append(census.Counts, *count)
+	}
+	sort.Slice(census.Counts, func(i, j int) bool {
+		a, b := census.Counts[i], census.Counts[j]
+		return a.Count > b.Count || a.Count == b.Count && a.Code < b.Code
+	})
+	return census, nil
+}
+
+// wrap moves a cell onto a board of the given size, unless the board is unbounded.
+func wrap(cell util.Cell, width, height int) util.Cell {
+	if width > 0 && height > 0 {
+		cell.X = (cell.X%width + width) % width
+		cell.Y = (cell.Y%height + height) % height
+	}
+	return cell
+}
+
+// group separates the cells into objects, row by row from the first cell of each.
+// The cells of an object that wraps around the edges of the board are moved next to each other,
+// so some of them can lie outside of the board.
+func group(cells []util.Cell, width, height int) [][]util.Cell {
+	alive := make(map[util.Cell]bool, len(cells))
+	for _, cell := range cells {
+		alive[wrap(cell, width, height)] = true
+	}
+	sorted := make([]util.Cell, 0, len(alive))
+	for cell := range alive {
+		sorted = append(sorted, cell)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Y < sorted[j].Y || sorted[i].Y == sorted[j].Y && sorted[i].X < sorted[j].X
+	})
+
+	var objects [][]util.Cell
+	for _, first := range sorted {
+		if !alive[first] {
+			continue
+		}
+		delete(alive, first)
+		object := []util.Cell{first}
+		for i := 0; i < len(object); i++ {
+			for dy := -groupDistance; dy <= groupDistance; dy++ {
+				for dx := -groupDistance; dx <= groupDistance; dx++ {
+					next := util.Cell{X: object[i].X + dx, Y: object[i].Y + dy}
+					if alive[wrap(next, width, height)] {
+						delete(alive, wrap(next, width, height))
+						object = append(object, next)
+					}
+				}
+			}
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+// WriteJSON writes the census as indented JSON.
+func (c *Census) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// WriteCSV writes the counts of the census as CSV, with a header row.
+func (c *Census) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"code", "name", "kind", "period", "count"})
+	for _, count := range c.Counts {
+		out.Write([]string{count.Code, count.Name, string(count.Kind), strconv.Itoa(count.Period), strconv.Itoa(count.Count)})
+	}
+	out.Flush()
+	return out.Error()
+}
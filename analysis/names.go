@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"sync"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// knownObjects are the common objects that are named in a census, each given by one of its phases,
+// with 'o' for alive cells. Their apgcodes are worked out from these the first time they are needed.
+var knownObjects = []struct {
+	name    string
+	pattern []string
+}{
+	{"block", []string{"oo", "oo"}},
+	{"beehive", []string{".oo.", "o..o", ".oo."}},
+	{"loaf", []string{".oo.", "o..o", ".o.o", "..o."}},
+	{"boat", []string{"oo.", "o.o", ".o."}},
+	{"ship", []string{"oo.", "o.o", ".oo"}},
+	{"tub", []string{".o.", "o.o", ".o."}},
+	{"pond", []string{".oo.", "o..o", "o..o", ".oo."}},
+	{"barge", []string{".o..", "o.o.", ".o.o", "..o."}},
+	{"long boat", []string{"oo..", "o.o.", ".o.o", "..o."}},
+	{"aircraft carrier", []string{"oo..", "o..o", "..oo"}},
+	{"snake", []string{"oo.o", "o.oo"}},
+	{"mango", []string{".oo..", "o..o.", ".o..o", "..oo."}},
+	{"eater 1", []string{"oo..", "o.o.", "..o.", "..oo"}},
+	{"bi-block", []string{"oo.oo", "oo.oo"}},
+	{"blinker", []string{"ooo"}},
+	{"toad", []string{".ooo", "ooo."}},
+	{"beacon", []string{"oo..", "oo..", "..oo", "..oo"}},
+	{"traffic light", []string{
+		"....o....",
+		"....o....",
+		"....o....",
+		".........",
+		"ooo...ooo",
+		".........",
+		"....o....",
+		"....o....",
+		"....o....",
+	}},
+	{"clock", []string{"..o.", "o.o.", ".o.o", ".o.."}},
+	{"pulsar", []string{
+		"..ooo...ooo..",
+		".............",
+		"o....o.o....o",
+		"o....o.o....o",
+		"o....o.o....o",
+		"..ooo...ooo..",
+		".............",
+		"..ooo...ooo..",
+		"o....o.o....o",
+		"o....o.o....o",
+		"o....o.o....o",
+		".............",
+		"..ooo...ooo..",
+	}},
+	{"pentadecathlon", []string{"..o....o..", "oo.oooo.oo", "..o....o.."}},
+	{"glider", []string{".o.", "..o", "ooo"}},
+	{"lightweight spaceship", []string{".o..o", "o....", "o...o", "oooo."}},
+	{"middleweight spaceship", []string{"...o..", ".o...o", "o.....", "o....o", "ooooo."}},
+	{"heavyweight spaceship", []string{"...oo..", ".o....o", "o......", "o.....o", "oooooo."}},
+}
+
+var (
+	namesOnce sync.Once
+	names     map[string]string
+	namesErr  error
+)
+
+// nameOf returns the common name of the object with the given apgcode, or "" if it does not have one.
+func nameOf(code string) (string, error) {
+	namesOnce.Do(func() {
+		names = make(map[string]string)
+		for _, known := range knownObjects {
+			var cells []util.Cell
+			for y, row := range known.pattern {
+				for x, c := range row {
+					if c == 'o' {
+						cells = append(cells, util.Cell{X: x, Y: y})
+					}
+				}
+			}
+			c, err := classify(cells)
+			if err != nil {
+				namesErr = err
+				return
+			}
+			names[c.code] = known.name
+		}
+	})
+	return names[code], namesErr
+}
@@ -0,0 +1,257 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// Kind is what an object does when it is evolved on its own.
+type Kind string
+
+const (
+	StillLife  Kind = "still life"
+	Oscillator Kind = "oscillator"
+	Spaceship  Kind = "spaceship"
+	// Unknown objects did not repeat themselves within maxPeriod turns, or died out.
+	Unknown Kind = "unknown"
+)
+
+// maxPeriod is the number of turns an object is evolved for to find its period.
+const maxPeriod = 128
+
+// groupDistance is how far apart two cells can be, counted in cells in any direction, and still be in the same object.
+// Cells two apart share a dead neighbour, so they can affect each other.
+const groupDistance = 2
+
+// shape is a set of alive cells moved so that their bounding box starts at 0, 0, sorted row by row.
+type shape []util.Cell
+
+// normalise returns the cells as a shape, and the top left corner of their bounding box.
+func normalise(cells []util.Cell) (shape, util.Cell) {
+	if len(cells) == 0 {
+		return shape{}, util.Cell{}
+	}
+	min := cells[0]
+	for _, cell := range cells {
+		if cell.X < min.X {
+			min.X = cell.X
+		}
+		if cell.Y < min.Y {
+			min.Y = cell.Y
+		}
+	}
+	s := make(shape, len(cells))
+	for i, cell := range cells {
+		s[i] = util.Cell{X: cell.X - min.X, Y: cell.Y - min.Y}
+	}
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Y < s[j].Y || s[i].Y == s[j].Y && s[i].X < s[j].X
+	})
+	return s, min
+}
+
+// size returns the width and height of the bounding box of the shape.
+func (s shape) size() (int, int) {
+	width, height := 0, 0
+	for _, cell := range s {
+		if cell.X >= width {
+			width = cell.X + 1
+		}
+		if cell.Y >= height {
+			height = cell.Y + 1
+		}
+	}
+	return width, height
+}
+
+func (s shape) equal(other shape) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if s[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// key returns a string that is the same for equal shapes.
+func (s shape) key() string {
+	var b strings.Builder
+	for _, cell := range s {
+		fmt.Fprintf(&b, "%v,%v;", cell.X, cell.Y)
+	}
+	return b.String()
+}
+
+// transforms returns the shape under each of the 8 rotations and reflections of the square.
+func (s shape) transforms() []shape {
+	shapes := make([]shape, 0, 8)
+	for t := 0; t < 8; t++ {
+		cells := make([]util.Cell, len(s))
+		for i, cell := range s {
+			x, y := cell.X, cell.Y
+			if t&1 != 0 {
+				x = -x
+			}
+			if t&2 != 0 {
+				y = -y
+			}
+			if t&4 != 0 {
+				x, y = y, x
+			}
+			cells[i] = util.Cell{X: x, Y: y}
+		}
+		transformed, _ := normalise(cells)
+		shapes = append(shapes, transformed)
+	}
+	return shapes
+}
+
+// wechslerDigits are the characters of the extended Wechsler format, for columns of 5 cells as 5 bit numbers.
+const wechslerDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// wechsler encodes the shape in the extended Wechsler format used by apgcodes: the rows are split into strips of 5,
+// each column of a strip is one digit with the top cell as its lowest bit, and the strips are separated by 'z'.
+// Runs of empty columns are shortened to 'w' for two, 'x' for three and 'y' followed by a digit for 4 to 39,
+// and the empty columns at the end of a strip are left out.
+func (s shape) wechsler() string {
+	width, height := s.size()
+	grid := make([][]bool, height)
+	for y := range grid {
+		grid[y] = make([]bool, width)
+	}
+	for _, cell := range s {
+		grid[cell.Y][cell.X] = true
+	}
+
+	var strips []string
+	for top := 0; top < height; top += 5 {
+		columns := make([]int, width)
+		for x := range columns {
+			for bit := 0; bit < 5 && top+bit < height; bit++ {
+				if grid[top+bit][x] {
+					columns[x] |= 1 << uint(bit)
+				}
+			}
+		}
+		for len(columns) > 0 && columns[len(columns)-1] == 0 {
+			columns = columns[:len(columns)-1]
+		}
+
+		var b strings.Builder
+		for x := 0; x < len(columns); {
+			if columns[x] != 0 {
+				b.WriteByte(wechslerDigits[columns[x]])
+				x++
+				continue
+			}
+			zeros := 0
+			for x+zeros < len(columns) && columns[x+zeros] == 0 {
+				zeros++
+			}
+			x += zeros
+			for zeros > 0 {
+				switch {
+				case zeros >= 4:
+					run := zeros
+					if run > 39 {
+						run = 39
+					}
+					b.WriteByte('y')
+					b.WriteByte(wechslerDigits[run-4])
+					zeros -= run
+				case zeros == 3:
+					b.WriteByte('x')
+					zeros = 0
+				case zeros == 2:
+					b.WriteByte('w')
+					zeros = 0
+				default:
+					b.WriteByte('0')
+					zeros = 0
+				}
+			}
+		}
+		strips = append(strips, b.String())
+	}
+	return strings.Join(strips, "z")
+}
+
+// canonical returns the Wechsler code of the shapes that comes first, shortest first and then alphabetically,
+// over every rotation and reflection of them.
+func canonical(shapes []shape) string {
+	best := ""
+	for _, s := range shapes {
+		for _, t := range s.transforms() {
+			code := t.wechsler()
+			if best == "" || len(code) < len(best) || len(code) == len(best) && code < best {
+				best = code
+			}
+		}
+	}
+	return best
+}
+
+// classification is what is found by evolving an object on its own.
+type classification struct {
+	code   string
+	kind   Kind
+	period int
+}
+
+// classify evolves the cells on an unbounded board until they repeat themselves, in the same place or moved,
+// and returns their apgcode, kind and period. The apgcode is "xs" and the population for still lifes,
+// "xp" and the period for oscillators, and "xq" and the period for spaceships, followed by the canonical Wechsler code
+// of all of its phases. Objects that do not repeat within maxPeriod turns are Unknown, with the code of their cells.
+func classify(cells []util.Cell) (classification, error) {
+	start, _ := normalise(cells)
+	width, height := start.size()
+	board := make([][]byte, height)
+	for y := range board {
+		board[y] = make([]byte, width)
+	}
+	for _, cell := range start {
+		board[cell.Y][cell.X] = 255
+	}
+
+	sim, err := gol.NewSimulation(gol.WithBoard(board), gol.WithParams(gol.Params{Threads: 1, Unbounded: true}))
+	if err != nil {
+		return classification{}, err
+	}
+	defer sim.Close()
+	phases := []shape{start}
+	for turn := 1; turn <= maxPeriod; turn++ {
+		if err := sim.Step(1); err != nil {
+			return classification{}, err
+		}
+		phase, at := normalise(sim.AliveCells())
+		if len(phase) == 0 {
+			break
+		}
+		if !phase.equal(start) {
+			phases = append(phases, phase)
+			continue
+		}
+		c := classification{code: canonical(phases), period: turn}
+		switch {
+		case at != (util.Cell{}):
+			// The board started with its top left corner at 0, 0, so it has moved.
+			c.kind = Spaceship
+			c.code = fmt.Sprintf("xq%v_%v", turn, c.code)
+		case turn == 1:
+			c.kind = StillLife
+			c.code = fmt.Sprintf("xs%v_%v", len(start), c.code)
+		default:
+			c.kind = Oscillator
+			c.code = fmt.Sprintf("xp%v_%v", turn, c.code)
+		}
+		return c, nil
+	}
+	return classification{code: "unknown_" + canonical([]shape{start}), kind: Unknown}, nil
+}
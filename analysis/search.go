@@ -0,0 +1,161 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+// maxSeeds is the number of soups an object's entry in a catalogue lists, to reproduce it from.
+const maxSeeds = 3
+
+// SearchParams configures a soup search.
+type SearchParams struct {
+	// Seed is the master seed the seed of every soup is picked from, so a search can be repeated exactly.
+	Seed     int64        `json:"seed"`
+	Soups    int          `json:"soups"`
+	Width    int          `json:"width"`
+	Height   int          `json:"height"`
+	Density  float64      `json:"density"`
+	Symmetry gol.Symmetry `json:"symmetry"`
+	// MaxTurns is how long a soup is evolved for to stabilise. Soups that have not by then are not counted.
+	MaxTurns int `json:"maxTurns"`
+	// Threads is the number of soups evolved at once, each by a single worker.
+	Threads int `json:"threads"`
+}
+
+// Entry is the record of one kind of object in a catalogue.
+type Entry struct {
+	Code   string `json:"code"`
+	Name   string `json:"name,omitempty"`
+	Kind   Kind   `json:"kind"`
+	Period int    `json:"period"`
+	// Count is the number of these objects found, and Soups the number of soups they were found in.
+	Count int `json:"count"`
+	Soups int `json:"soups"`
+	// Rare is set for objects found in at most one in a thousand soups, or in a single soup of a smaller search.
+	Rare bool `json:"rare"`
+	// Seeds are the seeds of the first soups the object was found in.
+	Seeds []int64 `json:"seeds"`
+}
+
+// Catalogue is the result of a soup search.
+type Catalogue struct {
+	Params SearchParams `json:"params"`
+	// Objects are sorted with the most common first, then by apgcode.
+	Objects []Entry `json:"objects"`
+	// Unstable are the seeds of the soups that did not stabilise within MaxTurns turns.
+	Unstable []int64 `json:"unstable"`
+}
+
+// soupResult is the census of one soup, or nil if it did not stabilise.
+type soupResult struct {
+	census *Census
+	err    error
+}
+
+// Search evolves random soups until they stabilise and catalogues the objects they leave behind.
+// The soups are shared between p.Threads goroutines, which each evolve one soup at a time with a single worker,
+// and their results are combined in the order of the soups, so the catalogue only depends on the params.
+func Search(p SearchParams) (*Catalogue, error) {
+	if p.Soups < 0 || p.Threads <= 0 || p.MaxTurns < 0 {
+		return nil, fmt.Errorf("%w: cannot search %v soups for %v turns with %v threads", gol.ErrInvalidParams, p.Soups, p.MaxTurns, p.Threads)
+	}
+	r := rand.New(rand.NewSource(p.Seed))
+	seeds := make([]int64, p.Soups)
+	for i := range seeds {
+		seeds[i] = r.Int63()
+	}
+
+	results := make([]soupResult, p.Soups)
+	next := make(chan int)
+	done := make(chan bool)
+	for i := 0; i < p.Threads; i++ {
+		go func() {
+			for soup := range next {
+				census, err := runSoup(p, seeds[soup])
+				results[soup] = soupResult{census, err}
+			}
+			done <- true
+		}()
+	}
+	for soup := range seeds {
+		next <- soup
+	}
+	close(next)
+	for i := 0; i < p.Threads; i++ {
+		<-done
+	}
+
+	catalogue := &Catalogue{Params: p, Objects: []Entry{}, Unstable: []int64{}}
+	entries := make(map[string]*Entry)
+	for soup, result := range results {
+		if result.err != nil {
+			return nil, fmt.Errorf("soup %v with seed %v: %w", soup, seeds[soup], result.err)
+		}
+		if result.census == nil {
+			catalogue.Unstable = append(catalogue.Unstable, seeds[soup])
+			continue
+		}
+		for _, count := range result.census.Counts {
+			entry := entries[count.Code]
+			if entry == nil {
+				entry = &Entry{Code: count.Code, Name: count.Name, Kind: count.Kind, Period: count.Period}
+				entries[count.Code] = entry
+			}
+			entry.Count += count.Count
+			entry.Soups++
+			if len(entry.Seeds) < maxSeeds {
+				entry.Seeds = append(entry.Seeds, seeds[soup])
+			}
+		}
+	}
+
+	rare := p.Soups / 1000
+	if rare < 1 {
+		rare = 1
+	}
+	for _, entry := range entries {
+		entry.Rare = entry.Soups <= rare
+		catalogue.Objects = append(catalogue.Objects, *entry)
+	}
+	sort.Slice(catalogue.Objects, func(i, j int) bool {
+		a, b := catalogue.Objects[i], catalogue.Objects[j]
+		return a.Count > b.Count || a.Count == b.Count && a.Code < b.Code
+	})
+	return catalogue, nil
+}
+
+// runSoup evolves the soup with the given seed until it stabilises and returns its census,
+// or nil if it does not stabilise within p.MaxTurns turns.
+func runSoup(p SearchParams, seed int64) (*Census, error) {
+	board, err := gol.Soup{Seed: seed, Density: p.Density, Symmetry: p.Symmetry}.Board(p.Width, p.Height)
+	if err != nil {
+		return nil, err
+	}
+	params := gol.Params{Threads: 1, Turns: p.MaxTurns, UntilStable: true}
+	sim, err := gol.NewSimulation(gol.WithBoard(board), gol.WithParams(params))
+	if err != nil {
+		return nil, err
+	}
+	defer sim.Close()
+	if err := sim.Run(context.Background()); err != nil {
+		return nil, err
+	}
+	if sim.Period() == 0 {
+		return nil, nil
+	}
+	return TakeCensus(sim.AliveCells(), p.Width, p.Height)
+}
+
+// WriteJSON writes the catalogue as indented JSON.
+func (c *Catalogue) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
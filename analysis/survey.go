@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+// Survey evolves the image in imageDir matching the params until it stabilises, or for p.Turns turns if it does not,
+// then takes a census of what is left. The census is written to outDir as <width>x<height>x<turn>.census.json
+// and <width>x<height>x<turn>.census.csv.
+func Survey(p gol.Params, imageDir, outDir string) (*Census, error) {
+	p.UntilStable = true
+	sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithImageDir(imageDir))
+	if err != nil {
+		return nil, err
+	}
+	defer sim.Close()
+	if err := sim.Run(context.Background()); err != nil {
+		return nil, err
+	}
+
+	width, height := p.ImageWidth, p.ImageHeight
+	if p.Unbounded {
+		width, height = 0, 0
+	}
+	census, err := TakeCensus(sim.AliveCells(), width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	name := filepath.Join(outDir, fmt.Sprintf("%vx%vx%v.census", p.ImageWidth, p.ImageHeight, sim.Turn()))
+	if err := writeFile(name+".json", census.WriteJSON); err != nil {
+		return nil, err
+	}
+	if err := writeFile(name+".csv", census.WriteCSV); err != nil {
+		return nil, err
+	}
+	return census, nil
+}
+
+// writeFile creates the file at path and writes it with write.
+func writeFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Println("File", filepath.Base(path), "output done!")
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+// Bench test with hardcoded parameters.
+func BenchmarkGol(b *testing.B) {
+	p := gol.Params{
+		Turns:       100,
+		ImageWidth:  64,
+		ImageHeight: 64,
+	}
+	for i := 1; i <= 16; i++ {
+		p.Threads = i
+		testName := fmt.Sprintf("%dx%dx%d-%d", p.ImageWidth, p.ImageHeight, p.Turns, p.Threads)
+		b.Run(testName, func(b *testing.B) {
+			os.Stdout = nil
+			events := make(chan gol.Event)
+			gol.Run(p, events, nil)
+			for event := range events {
+				switch event.(type) {
+				case gol.FinalTurnComplete:
+					break
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLocal measures how long the local backend takes to evolve a 512x512 image by a single turn,
+// including finding the cells that flipped.
+func BenchmarkLocal(b *testing.B) {
+	for _, threads := range []int{1, 2, 4, 8, 16} {
+		p := gol.Params{ImageWidth: 512, ImageHeight: 512, Threads: threads}
+		b.Run(fmt.Sprintf("%dx%d-%d", p.ImageWidth, p.ImageHeight, p.Threads), func(b *testing.B) {
+			sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(gol.NewLocal()))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer sim.Close()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := sim.Step(1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLocalSettled measures a turn of a 512x512 board that has settled into a single blinker,
+// so only the tiles around it have to be evolved.
+func BenchmarkLocalSettled(b *testing.B) {
+	board := make([][]byte, 512)
+	for y := range board {
+		board[y] = make([]byte, 512)
+	}
+	board[100][99], board[100][100], board[100][101] = 255, 255, 255
+	for _, threads := range []int{1, 8} {
+		p := gol.Params{ImageWidth: 512, ImageHeight: 512, Threads: threads}
+		b.Run(fmt.Sprintf("%dx%d-%d", p.ImageWidth, p.ImageHeight, p.Threads), func(b *testing.B) {
+			sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBoard(board), gol.WithBackend(gol.NewLocal()))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer sim.Close()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := sim.Step(1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLocalMetrics is BenchmarkLocal with every turn recorded to a metrics file,
+// to compare against it when deciding whether to leave metrics on.
+func BenchmarkLocalMetrics(b *testing.B) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, threads := range []int{1, 8} {
+		p := gol.Params{ImageWidth: 512, ImageHeight: 512, Threads: threads, Metrics: filepath.Join(dir, "metrics.csv")}
+		b.Run(fmt.Sprintf("%dx%d-%d", p.ImageWidth, p.ImageHeight, p.Threads), func(b *testing.B) {
+			sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(gol.NewLocal()))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer sim.Close()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := sim.Step(1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
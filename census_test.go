@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/analysis"
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// place returns the alive cells of a pattern of 'o's and '.'s with its top left corner at x, y.
+func place(pattern []string, x, y int) []util.Cell {
+	var cells []util.Cell
+	for dy, row := range pattern {
+		for dx, c := range row {
+			if c == 'o' {
+				cells = append(cells, util.Cell{X: x + dx, Y: y + dy})
+			}
+		}
+	}
+	return cells
+}
+
+// TestCensus checks that objects are separated, classified and named, whatever their phase and orientation,
+// including one that wraps around the edges of the board, and that the census is written as JSON and CSV.
+func TestCensus(t *testing.T) {
+	objects := []struct {
+		pattern []string
+		code    string
+		name    string
+		kind    analysis.Kind
+		period  int
+	}{
+		{[]string{"oo", "oo"}, "xs4_33", "block", analysis.StillLife, 1},
+		{[]string{".o.", "o.o", "o.o", ".o."}, "xs6_696", "beehive", analysis.StillLife, 1},
+		{[]string{".o.", "o.o", ".oo"}, "xs5_253", "boat", analysis.StillLife, 1},
+		{[]string{"ooo"}, "xp2_7", "blinker", analysis.Oscillator, 2},
+		{[]string{"oo..", "o...", "...o", "..oo"}, "xp2_318c", "beacon", analysis.Oscillator, 2},
+		{[]string{"..o....o..", "oo.oooo.oo", "..o....o.."}, "xp15_4r4z4r4", "pentadecathlon", analysis.Oscillator, 15},
+		// A glider reflected and in a different phase from the one it is named by.
+		{[]string{"o.o", "oo.", ".o."}, "xq4_153", "glider", analysis.Spaceship, 4},
+		{[]string{"o..o.", "....o", "o...o", ".oooo"}, "xq4_6frc", "lightweight spaceship", analysis.Spaceship, 4},
+		{[]string{"oo.o", "o.oo", "...."}, "xs6_bd", "snake", analysis.StillLife, 1},
+	}
+
+	const size = 64
+	var cells []util.Cell
+	for i, object := range objects {
+		cells = append(cells, place(object.pattern, 4+i%4*15, 4+i/4*15)...)
+	}
+	// A block split between the four corners of the board.
+	cells = append(cells, util.Cell{X: 0, Y: 0}, util.Cell{X: size - 1, Y: 0}, util.Cell{X: 0, Y: size - 1}, util.Cell{X: size - 1, Y: size - 1})
+
+	census, err := analysis.TakeCensus(cells, size, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(census.Objects) != len(objects)+1 {
+		t.Fatalf("expected %v objects, got %v: %v", len(objects)+1, len(census.Objects), census.Objects)
+	}
+	counts := make(map[string]analysis.Count)
+	for _, count := range census.Counts {
+		counts[count.Code] = count
+	}
+	for _, object := range objects {
+		expected := analysis.Count{Code: object.code, Name: object.name, Kind: object.kind, Period: object.period, Count: 1}
+		if object.code == "xs4_33" {
+			expected.Count = 2
+		}
+		if counts[object.code] != expected {
+			t.Errorf("expected %+v, got %+v", expected, counts[object.code])
+		}
+	}
+	if first := census.Counts[0]; first.Name != "block" {
+		t.Errorf("expected the blocks to be counted first, got %+v", first)
+	}
+	if block := census.Objects[0]; block.Code != "xs4_33" || block.X != size-1 || block.Y != size-1 {
+		t.Errorf("expected the wrapped block at %v,%v first, got %+v", size-1, size-1, block)
+	}
+
+	var decoded analysis.Census
+	var buffer bytes.Buffer
+	if err := census.WriteJSON(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Objects) != len(census.Objects) || len(decoded.Counts) != len(census.Counts) {
+		t.Errorf("the JSON has %v objects and %v counts, expected %v and %v",
+			len(decoded.Objects), len(decoded.Counts), len(census.Objects), len(census.Counts))
+	}
+
+	buffer.Reset()
+	if err := census.WriteCSV(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(census.Counts)+1 || records[1][0] != "xs4_33" || records[1][4] != "2" {
+		t.Errorf("unexpected CSV %v", records)
+	}
+}
+
+// TestSurvey checks the census of what the 64x64 image leaves behind once it stabilises.
+func TestSurvey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := gol.Params{ImageWidth: 64, ImageHeight: 64, Threads: 8, Turns: 10000}
+	census, err := analysis.Survey(p, "images", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	population := 0
+	for _, object := range census.Objects {
+		population += object.Population
+		if object.Kind == analysis.Unknown {
+			t.Errorf("expected the ash to settle into known kinds of object, got %+v", object)
+		}
+	}
+	if population != 101 {
+		t.Errorf("expected the objects to hold the 101 cells alive at turn 1577, got %v", population)
+	}
+	for _, ext := range []string{".json", ".csv"} {
+		if _, err := os.Stat(filepath.Join(dir, "64x64x1577.census"+ext)); err != nil {
+			t.Error(err)
+		}
+	}
+}
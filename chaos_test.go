@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/clustertest"
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// TestChaos runs a 64x64 image for 100 turns on engines whose connections have faults injected into them.
+// Slow connections and duplicated responses must not change the result, while dropped calls and disconnects
+// must either not happen to matter or make the simulation fail with an error saying which connection broke.
+func TestChaos(t *testing.T) {
+	tests := []struct {
+		name   string
+		option func(clustertest.Chaos) clustertest.Option
+		chaos  clustertest.Chaos
+		// fails is what the error must contain if the simulation fails. If empty, it must not fail.
+		fails []string
+	}{
+		{
+			name:   "slow workers",
+			option: clustertest.WithWorkerChaos,
+			chaos:  clustertest.Chaos{Latency: time.Millisecond},
+		},
+		{
+			name:   "slow controller",
+			option: clustertest.WithControllerChaos,
+			chaos:  clustertest.Chaos{Latency: time.Millisecond},
+		},
+		{
+			name:   "duplicated worker responses",
+			option: clustertest.WithWorkerChaos,
+			chaos:  clustertest.Chaos{Duplicate: 0.3},
+		},
+		{
+			name:   "duplicated controller responses",
+			option: clustertest.WithControllerChaos,
+			chaos:  clustertest.Chaos{Duplicate: 0.3},
+		},
+		{
+			name:   "dropped worker calls",
+			option: clustertest.WithWorkerChaos,
+			chaos:  clustertest.Chaos{Drop: 0.01},
+			fails:  []string{"cluster: advance", "worker", clustertest.ErrDropped.Error()},
+		},
+		{
+			name:   "dropped controller calls",
+			option: clustertest.WithControllerChaos,
+			chaos:  clustertest.Chaos{Drop: 0.01},
+			fails:  []string{"cluster: advance", clustertest.ErrDropped.Error()},
+		},
+		{
+			name:   "disconnected worker",
+			option: clustertest.WithWorkerChaos,
+			chaos:  clustertest.Chaos{Disconnect: 0.01},
+			fails:  []string{"cluster: advance", "worker", "shut down"},
+		},
+		{
+			name:   "disconnected controller",
+			option: clustertest.WithControllerChaos,
+			chaos:  clustertest.Chaos{Disconnect: 0.01},
+			fails:  []string{"cluster: advance", "shut down"},
+		},
+	}
+	p := gol.Params{ImageWidth: 64, ImageHeight: 64, Threads: 1}
+	expected := util.ReadAliveCells("check/images/64x64x100.pgm", 64, 64)
+	for _, test := range tests {
+		for seed := int64(1); seed <= 3; seed++ {
+			test.chaos.Seed = seed
+			t.Run(fmt.Sprintf("%v-%d", test.name, seed), func(t *testing.T) {
+				c, err := clustertest.Start(clustertest.Pipe, 3, 2, test.option(test.chaos))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer c.Close()
+				b, err := c.Backend()
+				if err != nil {
+					t.Fatal(err)
+				}
+				sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(b))
+				if err != nil {
+					// Loading the board is a call to the engine too, so it can fail in the same way.
+					if len(test.fails) == 0 {
+						t.Fatal(err)
+					}
+					checkChaosError(t, err, []string{"cluster: load"})
+					return
+				}
+				defer sim.Close()
+
+				for turn := 0; turn < 100; turn++ {
+					if err = sim.Step(1); err != nil {
+						break
+					}
+				}
+				if err != nil {
+					checkChaosError(t, err, test.fails)
+					return
+				}
+				assertEqualBoard(t, sim.AliveCells(), expected, p)
+			})
+		}
+	}
+}
+
+// checkChaosError fails the test unless the error contains every part of one of the expected messages.
+func checkChaosError(t *testing.T, err error, expected ...[]string) {
+	for _, parts := range expected {
+		matches := len(parts) > 0
+		for _, part := range parts {
+			matches = matches && strings.Contains(err.Error(), part)
+		}
+		if matches {
+			t.Log("failed as expected:", err)
+			return
+		}
+	}
+	t.Errorf("unexpected error: %v", err)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/clustertest"
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// TestClusterWorkers runs square and non-square images for 100 turns on engines started inside the test,
+// with 1-4 workers of 1 or 3 threads, connected over tcp and net.Pipe.
+// The 3x40 image has fewer columns than the engine has workers, and the 40x3 image fewer rows than the workers have threads.
+func TestClusterWorkers(t *testing.T) {
+	for _, transport := range []clustertest.Transport{clustertest.TCP, clustertest.Pipe} {
+		for workers := 1; workers <= 4; workers++ {
+			for _, threads := range []int{1, 3} {
+				name := fmt.Sprintf("%v-%dworkers-%dthreads", transport, workers, threads)
+				t.Run(name, func(t *testing.T) {
+					c, err := clustertest.Start(transport, workers, threads)
+					if err != nil {
+						t.Fatal(err)
+					}
+					defer c.Close()
+					for _, size := range [][2]int{{16, 16}, {64, 64}, {64, 16}, {16, 64}, {100, 37}, {3, 40}, {40, 3}} {
+						p := gol.Params{ImageWidth: size[0], ImageHeight: size[1], Threads: 1}
+						b, err := c.Backend()
+						if err != nil {
+							t.Fatal(err)
+						}
+						sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(b))
+						if err != nil {
+							t.Fatal(err)
+						}
+						if err := sim.Step(100); err != nil {
+							t.Fatal(err)
+						}
+						expected := util.ReadAliveCells(fmt.Sprintf("check/images/%vx%vx100.pgm", p.ImageWidth, p.ImageHeight), p.ImageWidth, p.ImageHeight)
+						assertEqualBoard(t, sim.AliveCells(), expected, p)
+						sim.Close()
+					}
+				})
+			}
+		}
+	}
+}
+
+// TestClusterPause checks that while one controller has the engine paused it can still use it,
+// while the calls of a second controller wait until it is unpaused, and the second controller cannot unpause it.
+func TestClusterPause(t *testing.T) {
+	c, err := clustertest.Start(clustertest.Pipe, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	paused, err := c.Backend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer paused.Stop()
+	other, err := c.Backend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Stop()
+
+	p := gol.Params{ImageWidth: 16, ImageHeight: 16, Threads: 1}
+	world := newBoardOf(16, 16)
+	// A vertical blinker.
+	world[1][2], world[2][2], world[3][2] = 255, 255, 255
+	if err := paused.Load(p, world); err != nil {
+		t.Fatal(err)
+	}
+	if err := paused.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 2)
+	go func() {
+		_, err := other.Advance(1)
+		done <- err
+	}()
+	go func() {
+		done <- other.Load(p, newBoardOf(16, 16))
+	}()
+
+	// The controller that paused the engine is not blocked by its own lock.
+	flipped, err := paused.Advance(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flipped) != 4 {
+		t.Errorf("expected the blinker to flip 4 cells, got %v", flipped)
+	}
+	if _, err := paused.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Resume(); err == nil {
+		t.Error("expected a controller that did not pause the engine to be unable to unpause it")
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("a call from another controller returned while the engine was paused, with %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := paused.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestClusterPauseLease checks that pausing twice from the same controller does not block it,
+// that a controller keeps the engine paused for longer than the lease of its pause by renewing it,
+// and that the pause of a controller that disconnects without resuming is released once its lease runs out.
+func TestClusterPauseLease(t *testing.T) {
+	lease := 100 * time.Millisecond
+	c, err := clustertest.Start(clustertest.Pipe, 1, 1, clustertest.WithPauseLease(lease))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	paused, err := c.Backend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := c.Backend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Stop()
+
+	p := gol.Params{ImageWidth: 16, ImageHeight: 16, Threads: 1}
+	if err := paused.Load(p, newBoardOf(16, 16)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		pausing := make(chan error, 1)
+		go func() {
+			pausing <- paused.Pause()
+		}()
+		select {
+		case err := <-pausing:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("pause %v from the controller that paused the engine blocked", i+1)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := other.Advance(1)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("a call from another controller returned after the lease of a renewed pause, with %v", err)
+	case <-time.After(5 * lease):
+	}
+
+	// Disconnecting without resuming leaves the pause to run out.
+	if err := paused.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(20 * lease):
+		t.Fatal("the pause of a controller that disconnected was never released")
+	}
+}
+
+// newBoardOf returns a board of dead cells with the given size.
+func newBoardOf(width, height int) [][]byte {
+	board := make([][]byte, height)
+	for y := range board {
+		board[y] = make([]byte, width)
+	}
+	return board
+}
+
+// TestWorkerThreads checks that a worker given fewer than one thread still calculates the tiles it is sent, using one.
+func TestWorkerThreads(t *testing.T) {
+	blinker := [][]byte{
+		{0, 0, 0, 0, 0},
+		{0, 0, 255, 0, 0},
+		{0, 0, 255, 0, 0},
+		{0, 0, 255, 0, 0},
+		{0, 0, 0, 0, 0},
+	}
+	expected := [][]byte{
+		{0, 0, 0},
+		{255, 255, 255},
+		{0, 0, 0},
+	}
+	for _, threads := range []int{0, -1} {
+		var res gol.TilesReport
+		if err := gol.NewWorker(threads).CalculateTiles(gol.TilesRequest{Tiles: [][][]byte{blinker}}, &res); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Done || len(res.Tiles) != 1 || fmt.Sprint(res.Tiles[0]) != fmt.Sprint(expected) {
+			t.Errorf("expected a worker of %v threads to calculate %v, got %v", threads, expected, res.Tiles)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package clustertest
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"io"
+	"math/rand"
+	"net/rpc"
+	"sync"
+	"time"
+)
+
+// ErrDropped is returned by calls that Chaos dropped before they were sent.
+var ErrDropped = errors.New("chaos: call dropped")
+
+// Chaos describes the faults injected into the calls made over a connection.
+// Every call rolls for each fault separately, using a random source seeded with Seed,
+// so that the same faults are injected into the same calls when they are made in the same order.
+type Chaos struct {
+	Seed int64
+	// Latency is the longest delay added before a call is sent. Every call is delayed by a random amount up to it.
+	Latency time.Duration
+	// Drop is the probability of a call failing with ErrDropped without being sent.
+	Drop float64
+	// Duplicate is the probability of the response to a call being delivered twice.
+	Duplicate float64
+	// Disconnect is the probability of the connection being closed before a call is sent.
+	// Every call made afterwards fails with rpc.ErrShutdown.
+	Disconnect float64
+}
+
+// chaosSource is the seeded random source that decides which faults are injected, shared by every connection.
+type chaosSource struct {
+	Chaos
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newChaosSource(chaos Chaos) *chaosSource {
+	return &chaosSource{
+		Chaos: chaos,
+		rand:  rand.New(rand.NewSource(chaos.Seed)),
+	}
+}
+
+// roll returns true with the given probability.
+func (s *chaosSource) roll(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rand.Float64() < probability
+}
+
+// delay returns a random latency up to the maximum.
+func (s *chaosSource) delay() time.Duration {
+	if s.Latency <= 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(s.rand.Int63n(int64(s.Latency)))
+}
+
+// newChaosClient creates an rpc client over conn that injects the faults decided by source.
+func newChaosClient(conn io.ReadWriteCloser, source *chaosSource) *rpc.Client {
+	buf := bufio.NewWriter(conn)
+	return rpc.NewClientWithCodec(&chaosCodec{
+		source: source,
+		rwc:    conn,
+		dec:    gob.NewDecoder(conn),
+		enc:    gob.NewEncoder(buf),
+		encBuf: buf,
+	})
+}
+
+// chaosCodec is the gob codec used by rpc.Dial, with faults injected into the calls it sends and the responses it reads.
+type chaosCodec struct {
+	source *chaosSource
+	rwc    io.ReadWriteCloser
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+
+	// duplicate is a response header to deliver again before reading the next one,
+	// and replayed is set while its body is being read.
+	duplicate *rpc.Response
+	replayed  bool
+}
+
+func (c *chaosCodec) WriteRequest(r *rpc.Request, body interface{}) error {
+	time.Sleep(c.source.delay())
+	if c.source.roll(c.source.Disconnect) {
+		c.rwc.Close()
+		return rpc.ErrShutdown
+	}
+	if c.source.roll(c.source.Drop) {
+		return ErrDropped
+	}
+	if err := c.enc.Encode(r); err != nil {
+		return err
+	}
+	if err := c.enc.Encode(body); err != nil {
+		return err
+	}
+	return c.encBuf.Flush()
+}
+
+func (c *chaosCodec) ReadResponseHeader(r *rpc.Response) error {
+	if c.duplicate != nil {
+		*r = *c.duplicate
+		c.duplicate = nil
+		c.replayed = true
+		return nil
+	}
+	if err := c.dec.Decode(r); err != nil {
+		return err
+	}
+	if c.source.roll(c.source.Duplicate) {
+		duplicate := *r
+		c.duplicate = &duplicate
+	}
+	return nil
+}
+
+func (c *chaosCodec) ReadResponseBody(body interface{}) error {
+	if c.replayed {
+		// The body of a duplicated response has already been read with the original,
+		// and the rpc client discards it anyway as the call is no longer pending.
+		c.replayed = false
+		return nil
+	}
+	return c.dec.Decode(body)
+}
+
+func (c *chaosCodec) Close() error {
+	return c.rwc.Close()
+}
@@ -0,0 +1,262 @@
+// Package clustertest starts an engine and its workers inside the current process,
+// so that the cluster backend can be tested without any other programs running.
+package clustertest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"strconv"
+	"sync"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+// Transport is how the controller, engine and workers of a Cluster connect to each other.
+type Transport int
+
+const (
+	// TCP listens on ephemeral ports on 127.0.0.1.
+	TCP Transport = iota
+	// Pipe connects through net.Pipe, without using the network at all.
+	Pipe
+)
+
+func (t Transport) String() string {
+	switch t {
+	case TCP:
+		return "tcp"
+	case Pipe:
+		return "pipe"
+	}
+	return "Incorrect Transport"
+}
+
+// Cluster is an engine and a number of workers registered with it.
+type Cluster struct {
+	// Address is the address of the engine, which can only be dialled with Dial when using Pipe.
+	Address string
+
+	transport Transport
+	pipes     *pipeNetwork
+	// workerChaos and controllerChaos inject faults into the calls from the engine to the workers
+	// and from the backends to the engine. They are nil if there are none.
+	workerChaos     *chaosSource
+	controllerChaos *chaosSource
+
+	pauseLease time.Duration
+
+	engine    *gol.Engine
+	listeners []net.Listener
+	closeOnce sync.Once
+}
+
+// Option configures a Cluster started with Start.
+type Option func(*Cluster)
+
+// WithWorkerChaos injects faults into the calls the engine makes to its workers.
+func WithWorkerChaos(chaos Chaos) Option {
+	return func(c *Cluster) {
+		c.workerChaos = newChaosSource(chaos)
+	}
+}
+
+// WithControllerChaos injects faults into the calls made to the engine by the backends returned by Backend.
+func WithControllerChaos(chaos Chaos) Option {
+	return func(c *Cluster) {
+		c.controllerChaos = newChaosSource(chaos)
+	}
+}
+
+// WithPauseLease sets how long the engine stays paused by a backend that stops renewing its pause.
+func WithPauseLease(lease time.Duration) Option {
+	return func(c *Cluster) {
+		c.pauseLease = lease
+	}
+}
+
+// Start starts an engine and the given number of workers, each using threads threads, and registers the workers.
+func Start(transport Transport, workers, threads int, opts ...Option) (*Cluster, error) {
+	if workers <= 0 || threads <= 0 {
+		return nil, fmt.Errorf("clustertest: at least 1 worker and thread are needed, not %v and %v", workers, threads)
+	}
+	c := &Cluster{
+		transport: transport,
+		pipes:     &pipeNetwork{listeners: map[string]*pipeListener{}},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.engine = gol.NewEngine()
+	if c.pauseLease > 0 {
+		gol.SetPauseLease(c.engine, c.pauseLease)
+	}
+	gol.SetDial(c.engine, func(address string) (*rpc.Client, error) {
+		return c.dialRPC(address, c.workerChaos)
+	})
+	// Closing the system stops the cluster instead of exiting the test.
+	gol.SetExit(c.engine, func() {})
+	l, err := c.listen("engine")
+	if err != nil {
+		return nil, err
+	}
+	c.Address = l.Addr().String()
+	go gol.ServeEngine(c.engine, l)
+
+	// Workers are registered without any faults injected.
+	client, err := c.dialRPC(c.Address, nil)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	defer client.Close()
+	for i := 0; i < workers; i++ {
+		worker := gol.NewWorker(threads)
+		gol.SetWorkerExit(worker, func() {})
+		l, err := c.listen("worker" + strconv.Itoa(i))
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		go gol.ServeWorker(worker, l)
+		if err := gol.RegisterWith(client, l.Addr().String()); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Dial connects to the engine, injecting the faults set by WithControllerChaos.
+func (c *Cluster) Dial() (*rpc.Client, error) {
+	return c.dialRPC(c.Address, c.controllerChaos)
+}
+
+// Backend returns a new cluster backend connected to the engine.
+func (c *Cluster) Backend() (gol.Backend, error) {
+	client, err := c.Dial()
+	if err != nil {
+		return nil, err
+	}
+	return gol.NewClusterClient(client), nil
+}
+
+// Close stops the engine and the workers from accepting connections and disconnects the engine from the workers.
+// Backends connected to the engine should be stopped before.
+func (c *Cluster) Close() error {
+	c.closeOnce.Do(func() {
+		for _, l := range c.listeners {
+			l.Close()
+		}
+		gol.CloseEngine(c.engine)
+	})
+	return nil
+}
+
+// listen creates a listener for a component of the cluster over its transport.
+func (c *Cluster) listen(name string) (net.Listener, error) {
+	var l net.Listener
+	if c.transport == Pipe {
+		l = c.pipes.listen(name)
+	} else {
+		var err error
+		l, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.listeners = append(c.listeners, l)
+	return l, nil
+}
+
+// dialRPC connects to a component of the cluster over its transport, injecting faults from chaos if it is not nil.
+func (c *Cluster) dialRPC(address string, chaos *chaosSource) (*rpc.Client, error) {
+	var conn net.Conn
+	var err error
+	if c.transport == Pipe {
+		conn, err = c.pipes.dial(address)
+	} else {
+		conn, err = net.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if chaos != nil {
+		return newChaosClient(conn, chaos), nil
+	}
+	return rpc.NewClient(conn), nil
+}
+
+// pipeNetwork connects dialers to listeners by name with net.Pipe.
+type pipeNetwork struct {
+	mu        sync.Mutex
+	listeners map[string]*pipeListener
+}
+
+func (n *pipeNetwork) listen(name string) *pipeListener {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	l := &pipeListener{
+		addr:   pipeAddr(name),
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+	n.listeners[name] = l
+	return l
+}
+
+func (n *pipeNetwork) dial(name string) (net.Conn, error) {
+	n.mu.Lock()
+	l, ok := n.listeners[name]
+	n.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("clustertest: nothing is listening on %v", name)
+	}
+	client, server := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.closed:
+		return nil, fmt.Errorf("clustertest: %v has stopped listening", name)
+	}
+}
+
+// pipeListener is a net.Listener that accepts the connections dialled through its pipeNetwork.
+type pipeListener struct {
+	addr      pipeAddr
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errors.New("clustertest: listener closed")
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return l.addr
+}
+
+// pipeAddr is the name a pipeListener listens on.
+type pipeAddr string
+
+func (a pipeAddr) Network() string {
+	return "pipe"
+}
+
+func (a pipeAddr) String() string {
+	return string(a)
+}
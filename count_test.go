@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// TestAlive checks the 512x512 cell counts reported on chosen turns, using a clock that only ticks on those turns.
+// You can manually check your counts by looking at CSVs provided in check/alive
+func TestAlive(t *testing.T) {
+	reportAt := map[int]bool{1: true, 2: true, 10: true, 50: true, 99: true}
+	clock := fakeClock{make(chan time.Time, 1)}
+	p := gol.Params{
+		Turns:       100,
+		Threads:     8,
+		ImageWidth:  512,
+		ImageHeight: 512,
+		Clock:       clock,
+	}
+	alive := readAliveCounts(p.ImageWidth, p.ImageHeight)
+	events := make(chan gol.Event)
+	gol.Run(p, events, nil)
+
+	// turn is the turn the next CellsFlipped event is sent for, the first one being the initially alive cells.
+	// The distributor only looks for a tick after it has sent the TurnComplete that follows the flipped cells of a turn,
+	// so a tick sent when they arrive is reported on exactly that turn.
+	turn := 0
+	reported := 0
+	for event := range events {
+		switch e := event.(type) {
+		case gol.CellsFlipped:
+			if reportAt[turn] {
+				clock.tick()
+			}
+			turn++
+		case gol.AliveCellsCount:
+			if !reportAt[e.CompletedTurns] {
+				t.Errorf("AliveCellsCount sent at turn %v, expected only turns %v", e.CompletedTurns, reportAt)
+			}
+			if expected := alive[e.CompletedTurns]; e.CellsCount != expected {
+				t.Errorf("At turn %v expected %v alive cells, got %v instead", e.CompletedTurns, expected, e.CellsCount)
+			}
+			reported++
+		}
+	}
+	if reported != len(reportAt) {
+		t.Errorf("%v AliveCellsCount events received, expected %v", reported, len(reportAt))
+	}
+}
+
+// fakeClock is a gol.Clock whose time stands still, and whose tickers only tick when tick is called.
+type fakeClock struct {
+	ticks chan time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return time.Time{}
+}
+
+func (c fakeClock) Sleep(time.Duration) {}
+
+func (c fakeClock) NewTicker(time.Duration) gol.Ticker {
+	return c
+}
+
+func (c fakeClock) C() <-chan time.Time {
+	return c.ticks
+}
+
+func (c fakeClock) Stop() {}
+
+func (c fakeClock) tick() {
+	c.ticks <- time.Time{}
+}
+
+func readAliveCounts(width, height int) map[int]int {
+	f, err := os.Open("check/alive/" + fmt.Sprintf("%vx%v.csv", width, height))
+	util.Check(err)
+	reader := csv.NewReader(f)
+	table, err := reader.ReadAll()
+	util.Check(err)
+	alive := make(map[int]int)
+	for i, row := range table {
+		if i == 0 {
+			continue
+		}
+		completedTurns, err := strconv.Atoi(row[0])
+		util.Check(err)
+		aliveCount, err := strconv.Atoi(row[1])
+		util.Check(err)
+		alive[completedTurns] = aliveCount
+	}
+	return alive
+}
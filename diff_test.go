@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/clustertest"
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+var (
+	diffRuns    = flag.Int("diff.runs", 4, "Number of random soups TestDifferential generates.")
+	diffSeed    = flag.Int64("diff.seed", 1, "Seed of the random soups TestDifferential generates.")
+	diffTurns   = flag.Int("diff.turns", 20, "Number of turns TestDifferential compares every soup for.")
+	diffMaxSize = flag.Int("diff.maxsize", 40, "Largest width and height of the soups TestDifferential generates.")
+	diffRect    = flag.Bool("diff.rect", true, "Generate non-square soups in TestDifferential as well as square ones.")
+)
+
+// primes are the sizes soups are given half of the time, as they never divide evenly between workers.
+var primes = []int{5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61}
+
+// TestDifferential evolves random soups with a simple reference stepper, the local backend with 1-16 threads
+// and an in-process cluster, and reports the first turn and cell where any of them differ from the reference.
+// Run it on more and bigger soups with e.g. 'go test -run TestDifferential -args -diff.runs=100 -diff.maxsize=200'.
+func TestDifferential(t *testing.T) {
+	c, err := clustertest.Start(clustertest.Pipe, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	r := rand.New(rand.NewSource(*diffSeed))
+	for run := 0; run < *diffRuns; run++ {
+		width := randomSize(r)
+		height := width
+		if *diffRect && r.Intn(2) == 0 {
+			height = randomSize(r)
+		}
+		soup := randomSoup(r, width, height)
+		reference := referenceTurns(soup, *diffTurns)
+
+		name := fmt.Sprintf("seed%d-run%d-%dx%d", *diffSeed, run, width, height)
+		t.Run(name, func(t *testing.T) {
+			for threads := 1; threads <= 16; threads++ {
+				p := gol.Params{ImageWidth: width, ImageHeight: height, Threads: threads}
+				compareWithReference(t, fmt.Sprintf("local backend with %d threads", threads), p, soup, reference, gol.NewLocal())
+			}
+			b, err := c.Backend()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := gol.Params{ImageWidth: width, ImageHeight: height, Threads: 1}
+			compareWithReference(t, "cluster backend with 2 workers", p, soup, reference, b)
+		})
+	}
+}
+
+// randomSize returns a prime half of the time, and any size from 4 up to the maximum otherwise.
+func randomSize(r *rand.Rand) int {
+	if r.Intn(2) == 0 {
+		var candidates []int
+		for _, prime := range primes {
+			if prime <= *diffMaxSize {
+				candidates = append(candidates, prime)
+			}
+		}
+		if len(candidates) > 0 {
+			return candidates[r.Intn(len(candidates))]
+		}
+	}
+	return 4 + r.Intn(*diffMaxSize-3)
+}
+
+// randomSoup returns a board where every cell is alive with the same random probability.
+// Sparse soups die out or settle in places quickly, so that the parts of the board which are still changing move around.
+func randomSoup(r *rand.Rand, width, height int) [][]byte {
+	density := 0.02 + r.Float64()*0.58
+	soup := make([][]byte, height)
+	for y := range soup {
+		soup[y] = make([]byte, width)
+		for x := range soup[y] {
+			if r.Float64() < density {
+				soup[y][x] = 255
+			}
+		}
+	}
+	return soup
+}
+
+// referenceTurns returns the board after every turn from 0 to turns, evolved as simply as possible.
+func referenceTurns(world [][]byte, turns int) [][][]byte {
+	height, width := len(world), len(world[0])
+	boards := [][][]byte{world}
+	for turn := 0; turn < turns; turn++ {
+		next := make([][]byte, height)
+		for y := range next {
+			next[y] = make([]byte, width)
+			for x := range next[y] {
+				neighbours := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if (dx != 0 || dy != 0) && world[(y+dy+height)%height][(x+dx+width)%width] == 255 {
+							neighbours++
+						}
+					}
+				}
+				if neighbours == 3 || neighbours == 2 && world[y][x] == 255 {
+					next[y][x] = 255
+				}
+			}
+		}
+		world = next
+		boards = append(boards, world)
+	}
+	return boards
+}
+
+// compareWithReference steps a Simulation one turn at a time and fails the test at the first turn
+// where its board differs from the reference, showing the cells around the first differing one.
+func compareWithReference(t *testing.T, name string, p gol.Params, soup [][]byte, reference [][][]byte, b gol.Backend) {
+	defer func() {
+		if err := recover(); err != nil {
+			t.Errorf("%v panicked: %v", name, err)
+		}
+	}()
+	sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBoard(soup), gol.WithBackend(b))
+	if err != nil {
+		t.Errorf("%v: %v", name, err)
+		return
+	}
+	defer sim.Close()
+
+	for turn := 1; turn < len(reference); turn++ {
+		if err := sim.Step(1); err != nil {
+			t.Errorf("%v failed on turn %v: %v", name, turn, err)
+			return
+		}
+		// The backend's own board is compared, as the Simulation's is only updated from the flipped cells.
+		board, err := b.Snapshot()
+		if err != nil {
+			t.Errorf("%v failed to return its board on turn %v: %v", name, turn, err)
+			return
+		}
+		for _, given := range [][][]byte{board, sim.Board()} {
+			if x, y, differs := firstDifference(given, reference[turn]); differs {
+				t.Errorf("%v differs from the reference on turn %v, first at cell (%v, %v):\n%v",
+					name, turn, x, y, util.WindowToString(given, reference[turn], x, y, 8))
+				return
+			}
+		}
+	}
+}
+
+// firstDifference returns the first cell, row by row, that differs between the boards.
+func firstDifference(given, expected [][]byte) (int, int, bool) {
+	for y := range expected {
+		for x := range expected[y] {
+			if y >= len(given) || x >= len(given[y]) || given[y][x] != expected[y][x] {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
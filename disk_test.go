@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// TestDiskBoard checks that boards imported into tiled files and evolved out of core match the expected images,
+// including sizes that are not a multiple of the tile size, that the files can be reopened,
+// and that a failed run does not leave its scratch board behind.
+func TestDiskBoard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, size := range [][2]int{{16, 16}, {64, 64}, {100, 37}, {3, 40}, {40, 3}} {
+		width, height := size[0], size[1]
+		p := gol.Params{ImageWidth: width, ImageHeight: height}
+		t.Run(fmt.Sprintf("%dx%d", width, height), func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("%vx%v.golt", width, height))
+			board, err := gol.ImportPgm(fmt.Sprintf("images/%vx%v.pgm", width, height), path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scratch, err := gol.CreateDiskBoard(path+".next", width, height)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for turn := 0; turn < 100; turn++ {
+				if err := gol.StepDisk(board, scratch, 3); err != nil {
+					t.Fatal(err)
+				}
+				board, scratch = scratch, board
+			}
+			if err := scratch.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if board.Turn() != 100 {
+				t.Errorf("expected the board to be at turn 100, got %v", board.Turn())
+			}
+			expected := util.ReadAliveCells(fmt.Sprintf("check/images/%vx%vx100.pgm", width, height), width, height)
+			if board.AliveCount() != len(expected) {
+				t.Errorf("expected %v alive cells, got %v", len(expected), board.AliveCount())
+			}
+			out := filepath.Join(dir, fmt.Sprintf("%vx%vx100.pgm", width, height))
+			if err := board.ExportPgm(out); err != nil {
+				t.Fatal(err)
+			}
+			assertEqualBoard(t, util.ReadAliveCells(out, width, height), expected, p)
+
+			// The turns alternate between the two files, so after an even number of them the board is back at path.
+			if err := board.Close(); err != nil {
+				t.Fatal(err)
+			}
+			board, err = gol.OpenDiskBoard(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer board.Close()
+			region := image.Rect(width/3, height/3, width, height)
+			var cells []util.Cell
+			for y, row := range board.Region(region) {
+				for x, cell := range row {
+					if cell == 255 {
+						cells = append(cells, util.Cell{X: x + region.Min.X, Y: y + region.Min.Y})
+					}
+				}
+			}
+			var inRegion []util.Cell
+			for _, cell := range expected {
+				if image.Pt(cell.X, cell.Y).In(region) {
+					inRegion = append(inRegion, cell)
+				}
+			}
+			assertEqualBoard(t, cells, inRegion, p)
+		})
+	}
+
+	t.Run("failed run", func(t *testing.T) {
+		path := filepath.Join(dir, "failed.golt")
+		// The image cannot be written into a file, so the run fails after its turns.
+		outDir := filepath.Join(dir, "not a directory")
+		if err := ioutil.WriteFile(outDir, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		p := gol.Params{ImageWidth: 16, ImageHeight: 16, Turns: 3, Threads: 1}
+		if err := gol.RunDisk(p, path, "images", outDir); err == nil {
+			t.Fatal("expected the image to fail to be written")
+		}
+		if _, err := os.Stat(path + ".next"); !os.IsNotExist(err) {
+			t.Errorf("expected the scratch board to be removed, got %v", err)
+		}
+	})
+
+	t.Run("bad file", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.golt")
+		if err := ioutil.WriteFile(path, []byte("GOLTILES but not a board"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gol.OpenDiskBoard(path); !errors.Is(err, gol.ErrBadDiskBoard) {
+			t.Errorf("expected %v, got %v", gol.ErrBadDiskBoard, err)
+		}
+	})
+}
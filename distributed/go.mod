@@ -1,5 +0,0 @@
-module uk.ac.bris.cs/gameoflife
-
-go 1.12
-
-require github.com/veandco/go-sdl2 v0.4.4
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// renderer keeps the board a replay has drawn so far, like SDL does.
+type renderer struct {
+	alive  map[util.Cell]bool
+	bounds gol.BoundsChanged
+}
+
+// receive reads Events until a TurnComplete and returns its turn, applying the flips before it.
+func (r *renderer) receive(t *testing.T, events <-chan gol.Event) int {
+	for event := range events {
+		switch e := event.(type) {
+		case gol.CellFlipped:
+			r.alive[e.Cell] = !r.alive[e.Cell]
+		case gol.CellsFlipped:
+			for _, cell := range e.Cells {
+				r.alive[cell] = !r.alive[cell]
+			}
+		case gol.BoundsChanged:
+			r.bounds = e
+		case gol.TurnComplete:
+			return e.CompletedTurns
+		}
+	}
+	t.Fatal("the replay stopped early")
+	return 0
+}
+
+// check fails unless the board drawn is the one the Simulation has after the given number of turns.
+func (r *renderer) check(t *testing.T, p gol.Params, board [][]byte, turns int) {
+	t.Helper()
+	sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBoard(board))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+	if err := sim.Step(turns); err != nil {
+		t.Fatal(err)
+	}
+	var drawn []util.Cell
+	for cell, alive := range r.alive {
+		if alive {
+			drawn = append(drawn, cell)
+		}
+	}
+	if len(drawn) != len(sim.AliveCells()) || !assertEqualBoard(t, drawn, sim.AliveCells(), sim.Params()) {
+		t.Fatalf("the board drawn at turn %v is wrong", turns)
+	}
+	if p.Unbounded && r.bounds.Bounds != sim.Bounds() {
+		t.Errorf("expected the bounds at turn %v to be %v, got %v", turns, sim.Bounds(), r.bounds.Bounds)
+	}
+}
+
+// TestEventLog records a run along with the keys pressed during it, then checks that the replay sends the same Events,
+// and that stepping and seeking forwards and backwards while paused draws the board of the turn they end on.
+func TestEventLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "run.gollog")
+
+	p := gol.Params{ImageWidth: 64, ImageHeight: 64, Turns: 100, Threads: 2}
+	recorder, err := gol.CreateEventLog(path, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan gol.Event, 1000)
+	keyPresses := make(chan rune, 10)
+	pressed := recorder.KeyPresses(keyPresses)
+	// Pause and resume straight away, so the keys are recorded before the run finishes.
+	pressed <- 'p'
+	pressed <- 'p'
+	gol.Run(p, events, keyPresses)
+	var recorded []gol.Event
+	for event := range recorder.Events(events) {
+		recorded = append(recorded, event)
+	}
+	if err := recorder.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// Keys pressed once the run has finished are not recorded.
+	pressed <- 'q'
+
+	log, err := gol.OpenEventLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.Params.ImageWidth != 64 || log.Params.ImageHeight != 64 || log.Params.Turns != 100 || log.Turns() != 100 {
+		t.Fatalf("expected a 64x64 log of 100 turns, got %+v ending at turn %v", log.Params, log.Turns())
+	}
+
+	replayed := make(chan gol.Event, 1000)
+	keys := make(chan rune, 10)
+	gol.Replay(log, 0, replayed, keys, nil)
+	board := &renderer{alive: make(map[util.Cell]bool)}
+	var keysReplayed []rune
+	for i := 0; i < len(recorded); i++ {
+		event := <-replayed
+		if e, ok := event.(gol.KeyPressed); ok {
+			keysReplayed = append(keysReplayed, e.Key)
+			i--
+			continue
+		}
+		if fmt.Sprintf("%T%v", event, event) != fmt.Sprintf("%T%v", recorded[i], recorded[i]) {
+			t.Fatalf("expected event %v to be %T%+v, got %T%+v", i, recorded[i], recorded[i], event, event)
+		}
+		if e, ok := event.(gol.CellsFlipped); ok {
+			for _, cell := range e.Cells {
+				board.alive[cell] = !board.alive[cell]
+			}
+		}
+	}
+	if string(keysReplayed) != "pp" {
+		t.Errorf("expected the keys pressed to be replayed, got %q", string(keysReplayed))
+	}
+
+	initial := util.ReadAliveCells(filepath.Join("images", "64x64.pgm"), 64, 64)
+	world := make([][]byte, 64)
+	for y := range world {
+		world[y] = make([]byte, 64)
+	}
+	for _, cell := range initial {
+		world[cell.Y][cell.X] = 255
+	}
+	board.check(t, p, world, 100)
+
+	steps := []struct {
+		keys string
+		turn int
+	}{
+		{"p50g", 50},
+		{",", 49},
+		{".", 50},
+		{"25n", 75},
+		{"g", 0},
+		{"200g", 100},
+	}
+	for _, step := range steps {
+		for _, key := range step.keys {
+			keys <- key
+		}
+		if turn := board.receive(t, replayed); turn != step.turn {
+			t.Fatalf("expected %q to go to turn %v, got %v", step.keys, step.turn, turn)
+		}
+		board.check(t, p, world, step.turn)
+	}
+	keys <- 'q'
+	for range replayed {
+	}
+}
+
+// TestEventLogUnbounded checks that seeking in the log of an unbounded board also moves its bounds,
+// in a log long enough to have boards kept along it to seek from.
+func TestEventLogUnbounded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "glider.gollog")
+
+	p := gol.Params{Threads: 1, Unbounded: true}
+	recorder, err := gol.CreateEventLog(path, gol.Params{ImageWidth: 5, ImageHeight: 5, Unbounded: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan gol.Event, 1000)
+	recorded := recorder.Events(events)
+	sim, err := gol.NewSimulation(gol.WithBoard(glider()), gol.WithParams(p), gol.WithEvents(events))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events <- gol.CellsFlipped{CompletedTurns: 0, Cells: sim.AliveCells()}
+	events <- gol.BoundsChanged{CompletedTurns: 0, Bounds: sim.Bounds()}
+	if err := sim.Step(600); err != nil {
+		t.Fatal(err)
+	}
+	sim.Close()
+	close(events)
+	for range recorded {
+	}
+
+	log, err := gol.OpenEventLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !log.Params.Unbounded || log.Turns() != 600 {
+		t.Fatalf("expected an unbounded log of 600 turns, got %+v ending at turn %v", log.Params, log.Turns())
+	}
+	replayed := make(chan gol.Event, 1000)
+	keys := make(chan rune, 10)
+	gol.Replay(log, 0, replayed, keys, nil)
+	board := &renderer{alive: make(map[util.Cell]bool)}
+	for board.receive(t, replayed) != 600 {
+	}
+	board.check(t, p, glider(), 600)
+	keys <- 'p'
+	for _, turn := range []int{300, 12, 599, 256, 0, 513} {
+		for _, key := range fmt.Sprintf("%vg", turn) {
+			keys <- key
+		}
+		board.receive(t, replayed)
+		board.check(t, p, glider(), turn)
+	}
+	keys <- 'q'
+	for range replayed {
+	}
+}
+
+// TestEventLogInvalid checks that logs of boards too small or large to draw, or with cells outside of their board,
+// are not read.
+func TestEventLogInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name          string
+		width, height int
+		cell          util.Cell
+	}{
+		{"empty", 0, 16, util.Cell{}},
+		{"huge", 16, 1 << 20, util.Cell{}},
+		{"cell past the edge", 16, 16, util.Cell{X: 3, Y: 16}},
+		{"cell before the edge", 16, 16, util.Cell{X: -1, Y: 3}},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, "bad.gollog")
+		recorder, err := gol.CreateEventLog(path, gol.Params{ImageWidth: test.width, ImageHeight: test.height})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events := make(chan gol.Event, 2)
+		events <- gol.CellsFlipped{CompletedTurns: 0, Cells: []util.Cell{test.cell}}
+		events <- gol.TurnComplete{CompletedTurns: 0}
+		close(events)
+		for range recorder.Events(events) {
+		}
+		if _, err := gol.OpenEventLog(path); !errors.Is(err, gol.ErrBadEventLog) {
+			t.Errorf("%v: expected %v, got %v", test.name, gol.ErrBadEventLog, err)
+		}
+	}
+}
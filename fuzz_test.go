@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// addPgmSeeds adds every image in images/ and check/images/ to the seed corpus, along with some broken headers,
+// passing the data and the size in the file name to add.
+func addPgmSeeds(f *testing.F, add func(data []byte, width, height int)) {
+	for _, pattern := range []string{"images/*.pgm", "check/images/*.pgm"} {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			f.Fatal(err)
+		}
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				f.Fatal(err)
+			}
+			size := strings.Split(strings.TrimSuffix(filepath.Base(path), ".pgm"), "x")
+			width, _ := strconv.Atoi(size[0])
+			height, _ := strconv.Atoi(size[1])
+			add(data, width, height)
+		}
+	}
+	for _, header := range []string{"", "P5", "P5\n", "P5 2 2 255\n", "P5\n2 2\n255", "P5\n-2 2\n255\n\x00\x00\x00\x00",
+		"P5\n2 -2\n255\n\x00\x00\x00\x00", "P5\n99999999999 99999999999\n255\n", "P5 #comment\n2 1 255\n\xff\x00", "P2\n2 1\n255\n0 0",
+		"P5\n2 1 00000000255\n\xff\x00", "P5\n" + strings.Repeat("1", 100)} {
+		add([]byte(header), 2, 2)
+	}
+}
+
+// FuzzDecodePgm checks that any data is either decoded into a board of the size in its header, or returns ErrBadPgm,
+// and that ReadPgmHeader agrees with it.
+// Run it with 'go test -run FuzzDecodePgm -fuzz FuzzDecodePgm'.
+func FuzzDecodePgm(f *testing.F) {
+	addPgmSeeds(f, func(data []byte, _, _ int) {
+		f.Add(data)
+	})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// The header read on its own, as images too large for memory are, must agree with the decoded one.
+		r := bytes.NewReader(data)
+		headerWidth, headerHeight, headerErr := util.ReadPgmHeader(r)
+		width, height, pixels, err := util.DecodePgm(data)
+		if err != nil {
+			if !errors.Is(err, util.ErrBadPgm) {
+				t.Errorf("expected %v, got %v", util.ErrBadPgm, err)
+			}
+			return
+		}
+		if width <= 0 || height <= 0 || len(pixels) != width*height {
+			t.Fatalf("decoded %v pixels for a %vx%v image", len(pixels), width, height)
+		}
+		if headerErr != nil || headerWidth != width || headerHeight != height || !bytes.HasPrefix(data[len(data)-r.Len():], pixels) {
+			t.Fatalf("read a %vx%v header with %v, decoded a %vx%v image", headerWidth, headerHeight, headerErr, width, height)
+		}
+		cells, err := util.DecodeAliveCells(data, width, height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cell := range cells {
+			if cell.X < 0 || cell.X >= width || cell.Y < 0 || cell.Y >= height || pixels[cell.Y*width+cell.X] == 0 {
+				t.Fatalf("cell %v is not alive in the %vx%v image", cell, width, height)
+			}
+		}
+	})
+}
+
+// FuzzSimulationImage checks that starting a Simulation from any image either loads a board of the expected size,
+// or returns ErrBadImage. Run it with 'go test -run FuzzSimulationImage -fuzz FuzzSimulationImage'.
+func FuzzSimulationImage(f *testing.F) {
+	addPgmSeeds(f, func(data []byte, width, height int) {
+		f.Add(data, width, height)
+	})
+	dir := f.TempDir()
+	f.Fuzz(func(t *testing.T, data []byte, width, height int) {
+		if width <= 0 || height <= 0 || width > 1024 || height > 1024 {
+			// Other sizes are rejected before the image is read, or need more memory than the test should use.
+			return
+		}
+		err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("%vx%v.pgm", width, height)), data, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(filepath.Join(dir, fmt.Sprintf("%vx%v.pgm", width, height)))
+
+		p := gol.Params{ImageWidth: width, ImageHeight: height, Threads: 1}
+		sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithImageDir(dir))
+		if err != nil {
+			if !errors.Is(err, gol.ErrBadImage) {
+				t.Errorf("expected %v, got %v", gol.ErrBadImage, err)
+			}
+			return
+		}
+		defer sim.Close()
+		board := sim.Board()
+		if len(board) != height || len(board[0]) != width {
+			t.Errorf("loaded a %vx%v board, expected %vx%v", len(board[0]), len(board), width, height)
+		}
+	})
+}
+
+// FuzzEventLog checks that any data is either read as an event log that can be replayed and sought through
+// without sending a cell outside of a bounded board, or returns ErrBadEventLog. Run it with 'go test -run FuzzEventLog -fuzz FuzzEventLog'.
+func FuzzEventLog(f *testing.F) {
+	path := filepath.Join(f.TempDir(), "seed.gollog")
+	recorder, err := gol.CreateEventLog(path, gol.Params{ImageWidth: 4, ImageHeight: 4, Turns: 2})
+	if err != nil {
+		f.Fatal(err)
+	}
+	events := make(chan gol.Event, 20)
+	for _, event := range []gol.Event{
+		gol.CellsFlipped{CompletedTurns: 0, Cells: []util.Cell{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}}},
+		gol.BoundsChanged{CompletedTurns: 0, Bounds: image.Rect(1, 0, 2, 3)},
+		gol.StateChange{CompletedTurns: 0, NewState: gol.Executing},
+		gol.CellsFlipped{CompletedTurns: 0, Cells: []util.Cell{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}}},
+		gol.TurnComplete{CompletedTurns: 1},
+		gol.AliveCellsCount{CompletedTurns: 1, CellsCount: 3},
+		gol.KeyPressed{CompletedTurns: 1, Key: 'p'},
+		gol.CellFlipped{CompletedTurns: 1, Cell: util.Cell{X: 3, Y: 3}},
+		gol.TurnComplete{CompletedTurns: 1},
+		gol.Stabilised{CompletedTurns: 2, Period: 2},
+		gol.FinalTurnComplete{CompletedTurns: 2, Alive: []util.Cell{{X: 0, Y: 1}}},
+		gol.ImageOutputComplete{CompletedTurns: 2, Filename: "4x4x2"},
+	} {
+		events <- event
+	}
+	close(events)
+	for range recorder.Events(events) {
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for i := 0; i <= len(data); i += 3 {
+		f.Add(data[:i])
+	}
+	f.Add(data)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		log, err := gol.ParseEventLog(data)
+		if err != nil {
+			if !errors.Is(err, gol.ErrBadEventLog) {
+				t.Errorf("expected %v, got %v", gol.ErrBadEventLog, err)
+			}
+			return
+		}
+		replayed := make(chan gol.Event, 100)
+		keys := make(chan rune, 10)
+		for _, key := range "p0g.99g,q" {
+			keys <- key
+		}
+		gol.Replay(log, 0, replayed, keys, nil)
+		for event := range replayed {
+			if log.Params.Unbounded {
+				continue
+			}
+			var cells []util.Cell
+			switch e := event.(type) {
+			case gol.CellFlipped:
+				cells = []util.Cell{e.Cell}
+			case gol.CellsFlipped:
+				cells = e.Cells
+			case gol.FinalTurnComplete:
+				cells = e.Alive
+			}
+			for _, cell := range cells {
+				if cell.X < 0 || cell.X >= log.Params.ImageWidth || cell.Y < 0 || cell.Y >= log.Params.ImageHeight {
+					t.Fatalf("replayed cell %v of a %vx%v board", cell, log.Params.ImageWidth, log.Params.ImageHeight)
+				}
+			}
+		}
+	})
+}
+
+// FuzzOpenDiskBoard checks that any file is either opened as a board whose cells can all be read, or returns ErrBadDiskBoard.
+// Run it with 'go test -run FuzzOpenDiskBoard -fuzz FuzzOpenDiskBoard'.
+func FuzzOpenDiskBoard(f *testing.F) {
+	dir := f.TempDir()
+	path := filepath.Join(dir, "board.golt")
+	for _, size := range [][2]int{{1, 1}, {70, 5}, {64, 128}} {
+		b, err := gol.CreateDiskBoard(path, size[0], size[1])
+		if err != nil {
+			f.Fatal(err)
+		}
+		if err := b.Close(); err != nil {
+			f.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		f.Add(data[:40])
+		f.Add(data[:len(data)-1])
+	}
+	f.Add([]byte{})
+	f.Add([]byte("GOLTILES"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		b, err := gol.OpenDiskBoard(path)
+		if err != nil {
+			if !errors.Is(err, gol.ErrBadDiskBoard) {
+				t.Errorf("expected %v, got %v", gol.ErrBadDiskBoard, err)
+			}
+			return
+		}
+		defer b.Close()
+		if b.Width <= 0 || b.Height <= 0 {
+			t.Fatalf("opened a %vx%v board", b.Width, b.Height)
+		}
+		alive := 0
+		for _, row := range b.Region(image.Rect(0, 0, b.Width, b.Height)) {
+			for _, cell := range row {
+				if cell != 0 {
+					alive++
+				}
+			}
+		}
+		// Cells past the edges of the board can be set in the last tiles, which only AliveCount counts.
+		if count := b.AliveCount(); alive > count {
+			t.Errorf("read %v alive cells, but AliveCount is %v", alive, count)
+		}
+	})
+}
@@ -1,6 +1,6 @@
 module uk.ac.bris.cs/gameoflife
 
-go 1.12
+go 1.18
 
 require (
 	github.com/ChrisGora/benchgraph v0.0.0-20190810104645-dd14afd4debc // indirect
@@ -0,0 +1,129 @@
+package gol
+
+import (
+	"errors"
+	"fmt"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// ErrUnknownBackend is returned when a backend is selected by a name that does not exist.
+var ErrUnknownBackend = errors.New("gol: unknown backend")
+
+// ErrNotLoaded is returned by a Local backend that is advanced before a board has been loaded.
+var ErrNotLoaded = errors.New("gol: no board has been loaded")
+
+// Backend evolves the board of a Simulation.
+// The Simulation keeps its own copy of the board, which it updates from the cells the Backend reports as flipped.
+type Backend interface {
+	// Load replaces the board that is evolved. It is called before the first turn and after every edit.
+	Load(p Params, world [][]byte) error
+	// Advance evolves the board by the given number of turns and returns the cells that are different afterwards.
+	Advance(turns int) ([]util.Cell, error)
+	// Snapshot returns a copy of the board as the Backend currently has it.
+	Snapshot() ([][]byte, error)
+	// Pause and Resume are called when the game is paused and resumed.
+	// Advance and Load are still called in between to step and edit the board.
+	Pause() error
+	Resume() error
+	// Stop releases the Backend. It is not used again afterwards.
+	Stop() error
+}
+
+// shutdowner is implemented by backends that can shut down the system they run on, which the 'k' key does.
+type shutdowner interface {
+	Shutdown() error
+}
+
+// NewBackend creates a Backend by name: "local" evolves the board with goroutines,
+// "cluster" on the engine at engineAddress and the workers registered with it.
+func NewBackend(name, engineAddress string) (Backend, error) {
+	switch name {
+	case "local":
+		return NewLocal(), nil
+	case "cluster":
+		return NewCluster(engineAddress)
+	}
+	return nil, fmt.Errorf("%w: %q, expected \"local\" or \"cluster\"", ErrUnknownBackend, name)
+}
+
+// Local is a Backend that evolves the board with a pool of worker goroutines, splitting its tiles between Params.Threads of them.
+// The workers run from the first Load until Stop.
+type Local struct {
+	pool *workerPool
+}
+
+// NewLocal creates a Local backend.
+func NewLocal() *Local {
+	return &Local{}
+}
+
+// Load replaces the board, keeping the workers running if the size and number of threads have not changed.
+func (l *Local) Load(p Params, world [][]byte) error {
+	if l.pool == nil || l.pool.p.Threads != p.Threads || l.pool.p.ImageWidth != p.ImageWidth || l.pool.p.ImageHeight != p.ImageHeight {
+		if l.pool != nil {
+			l.pool.stop()
+		}
+		l.pool = newWorkerPool(p)
+	}
+	l.pool.p = p
+	l.pool.load(world)
+	return nil
+}
+
+func (l *Local) Advance(turns int) ([]util.Cell, error) {
+	if l.pool == nil {
+		return nil, ErrNotLoaded
+	}
+	if turns == 1 {
+		return l.pool.advance(true), nil
+	}
+	before := copyWorld(l.pool.world)
+	for i := 0; i < turns; i++ {
+		l.pool.advance(false)
+	}
+	var flipped []util.Cell
+	for y := range l.pool.world {
+		for x := range l.pool.world[y] {
+			if l.pool.world[y][x] != before[y][x] {
+				flipped = append(flipped, util.Cell{X: x, Y: y})
+			}
+		}
+	}
+	return flipped, nil
+}
+
+func (l *Local) Snapshot() ([][]byte, error) {
+	if l.pool == nil {
+		return nil, ErrNotLoaded
+	}
+	return copyWorld(l.pool.world), nil
+}
+
+// Pause does nothing, as the board is only evolved while Advance is called.
+func (l *Local) Pause() error {
+	return nil
+}
+
+// Resume does nothing, as the board is only evolved while Advance is called.
+func (l *Local) Resume() error {
+	return nil
+}
+
+// Stop stops the workers.
+func (l *Local) Stop() error {
+	if l.pool != nil {
+		l.pool.stop()
+		l.pool = nil
+	}
+	return nil
+}
+
+// copyWorld returns a deep copy of a board.
+func copyWorld(world [][]byte) [][]byte {
+	c := make([][]byte, len(world))
+	for y, row := range world {
+		c[y] = append([]byte(nil), row...)
+	}
+	return c
+}
@@ -0,0 +1,59 @@
+package gol
+
+import "time"
+
+// defaultReportInterval is how often Run sends an AliveCellsCount Event when Params.ReportInterval is not set.
+const defaultReportInterval = 2 * time.Second
+
+// Clock is the source of time for Run's AliveCellsCount reports and turn rate limit.
+// Tests can replace the system clock with one that ticks exactly when they choose.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks on C like a time.Ticker, until it is stopped.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// systemClock is the Clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// clock returns the Clock of the params, or the system clock if there is none.
+func (p Params) clock() Clock {
+	if p.Clock == nil {
+		return systemClock{}
+	}
+	return p.Clock
+}
+
+// reportInterval returns how often AliveCellsCount Events are sent.
+func (p Params) reportInterval() time.Duration {
+	if p.ReportInterval == 0 {
+		return defaultReportInterval
+	}
+	return p.ReportInterval
+}
@@ -0,0 +1,135 @@
+package gol
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// Cluster is a Backend that evolves the board on an engine, which splits every turn between its workers.
+// The engine only runs one board at a time, so only one Cluster should be used with it at once.
+// While a Cluster has the engine paused, calls from any other Cluster wait until it resumes.
+// The pause is renewed in the background until then, so it is only released without Resume if the Cluster goes away.
+type Cluster struct {
+	client *rpc.Client
+	// id tells the engine which calls come from this Cluster.
+	id string
+	// renewing is closed to stop renewing the pause, and is nil while the engine is not paused by this Cluster.
+	mu       sync.Mutex
+	renewing chan struct{}
+}
+
+// NewCluster connects to the engine at engineAddress.
+func NewCluster(engineAddress string) (*Cluster, error) {
+	client, err := rpc.Dial("tcp", engineAddress)
+	if err != nil {
+		return nil, err
+	}
+	return NewClusterClient(client), nil
+}
+
+// NewClusterClient creates a Cluster that uses an existing connection to an engine, e.g. one over another transport.
+func NewClusterClient(client *rpc.Client) *Cluster {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(err)
+	}
+	return &Cluster{client: client, id: hex.EncodeToString(id[:])}
+}
+
+func (c *Cluster) Load(p Params, world [][]byte) error {
+	var x bool
+	err := c.client.Call(Load, LoadRequest{
+		Client:      c.id,
+		ImageHeight: p.ImageHeight,
+		ImageWidth:  p.ImageWidth,
+		World:       world,
+	}, &x)
+	return wrap("load", err)
+}
+
+func (c *Cluster) Advance(turns int) ([]util.Cell, error) {
+	res := new(AdvanceReport)
+	err := c.client.Call(Advance, AdvanceRequest{Client: c.id, Turns: turns}, res)
+	return res.Flipped, wrap("advance", err)
+}
+
+func (c *Cluster) Snapshot() ([][]byte, error) {
+	res := new(StatusReport)
+	err := c.client.Call(ReturnBoardState, c.id, res)
+	return res.World, wrap("snapshot", err)
+}
+
+// Pause locks the world on the engine, so that it can only be changed through this Cluster.
+func (c *Cluster) Pause() error {
+	res := new(PauseReport)
+	if err := c.client.Call(Pause, c.id, res); err != nil {
+		return wrap("pause", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.renewing == nil && res.Lease > 0 {
+		c.renewing = make(chan struct{})
+		go c.renew(res.Lease/3, c.renewing)
+	}
+	return nil
+}
+
+// renew renews the pause every interval until stop is closed or the engine cannot be reached.
+func (c *Cluster) renew(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			var x bool
+			if err := c.client.Call(RenewPause, c.id, &x); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *Cluster) Resume() error {
+	c.mu.Lock()
+	if c.renewing != nil {
+		close(c.renewing)
+		c.renewing = nil
+	}
+	c.mu.Unlock()
+	var x bool
+	return wrap("resume", c.client.Call(Unpause, c.id, &x))
+}
+
+// Stop disconnects from the engine, leaving it and its workers running.
+// A pause that has not been resumed is no longer renewed, so the engine releases it once its lease runs out.
+func (c *Cluster) Stop() error {
+	c.mu.Lock()
+	if c.renewing != nil {
+		close(c.renewing)
+		c.renewing = nil
+	}
+	c.mu.Unlock()
+	return c.client.Close()
+}
+
+// Shutdown closes the engine and every worker registered with it.
+func (c *Cluster) Shutdown() error {
+	var x bool
+	return wrap("shutdown", c.client.Call(CloseSystem, true, &x))
+}
+
+// wrap adds which call to the engine failed to an error.
+func wrap(call string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("cluster: %v: %w", call, err)
+}
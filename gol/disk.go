@@ -0,0 +1,381 @@
+package gol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// ErrBadDiskBoard is returned when a file is not a board created by CreateDiskBoard.
+var ErrBadDiskBoard = errors.New("gol: bad disk board")
+
+// diskTileSize is the width and height of the tiles a DiskBoard is stored in.
+// Every row of a tile is packed into the bits of one little endian uint64, so a tile takes 512 bytes.
+const diskTileSize = 64
+
+// diskHeaderSize is the length of the header at the start of a DiskBoard file:
+// diskMagic, then the width, height, tile size and turn as little endian uint64s.
+const diskHeaderSize = 40
+
+var diskMagic = [8]byte{'G', 'O', 'L', 'T', 'I', 'L', 'E', 'S'}
+
+// DiskBoard is a board stored in a file of bit packed tiles, which is memory mapped so that only the parts
+// of it in use are held in memory. Tiles are stored row by row, so a band of diskTileSize rows is one
+// contiguous part of the file. Boards far larger than memory can be evolved with StepDisk.
+type DiskBoard struct {
+	Width, Height  int
+	tilesX, tilesY int
+	file           *os.File
+	data           []byte
+}
+
+// CreateDiskBoard creates a board of dead cells with the given size at path, replacing any file there.
+func CreateDiskBoard(path string, width, height int) (*DiskBoard, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("%w: the board must be at least 1x1, not %vx%v", ErrInvalidParams, width, height)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	b := newDiskBoard(file, width, height)
+	var header [diskHeaderSize]byte
+	copy(header[:], diskMagic[:])
+	binary.LittleEndian.PutUint64(header[8:], uint64(width))
+	binary.LittleEndian.PutUint64(header[16:], uint64(height))
+	binary.LittleEndian.PutUint64(header[24:], diskTileSize)
+	if _, err := file.Write(header[:]); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Truncate(int64(b.size())); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return b, b.mmap()
+}
+
+// Turn returns the number of turns the board has been evolved for since it was created.
+func (b *DiskBoard) Turn() int {
+	return int(binary.LittleEndian.Uint64(b.data[32:]))
+}
+
+// OpenDiskBoard opens a board created by CreateDiskBoard. Changes to it are written back to the file.
+func OpenDiskBoard(path string) (*DiskBoard, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	var header [diskHeaderSize]byte
+	if _, err := io.ReadFull(file, header[:]); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%w: %v: %v", ErrBadDiskBoard, path, err)
+	}
+	width := binary.LittleEndian.Uint64(header[8:])
+	height := binary.LittleEndian.Uint64(header[16:])
+	tileSize := binary.LittleEndian.Uint64(header[24:])
+	// The size is limited so that the number of bytes in the file cannot overflow.
+	if string(header[:8]) != string(diskMagic[:]) || tileSize != diskTileSize || width == 0 || height == 0 || width > 1<<30 || height > 1<<30 {
+		file.Close()
+		return nil, fmt.Errorf("%w: %v has an invalid header", ErrBadDiskBoard, path)
+	}
+	b := newDiskBoard(file, int(width), int(height))
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() != int64(b.size()) {
+		file.Close()
+		return nil, fmt.Errorf("%w: %v has %v bytes, expected %v for a %vx%v board", ErrBadDiskBoard, path, info.Size(), b.size(), width, height)
+	}
+	return b, b.mmap()
+}
+
+func newDiskBoard(file *os.File, width, height int) *DiskBoard {
+	return &DiskBoard{
+		Width:  width,
+		Height: height,
+		tilesX: (width + diskTileSize - 1) / diskTileSize,
+		tilesY: (height + diskTileSize - 1) / diskTileSize,
+		file:   file,
+	}
+}
+
+// size returns the number of bytes in the file of the board.
+func (b *DiskBoard) size() int {
+	return diskHeaderSize + b.tilesX*b.tilesY*diskTileSize*8
+}
+
+// mmap maps the file of the board into memory, closing it if that fails.
+func (b *DiskBoard) mmap() error {
+	data, err := mapFile(b.file, b.size())
+	if err != nil {
+		b.file.Close()
+		return err
+	}
+	b.data = data
+	return nil
+}
+
+// Close writes the board back to its file and closes it. The board must not be used afterwards.
+func (b *DiskBoard) Close() error {
+	err := unmapFile(b.file, b.data)
+	b.data = nil
+	if closeErr := b.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// word returns the offset in the file of the row of a tile that holds the cells x to x+63 of row y,
+// where x is a multiple of diskTileSize.
+func (b *DiskBoard) word(x, y int) int {
+	tile := y/diskTileSize*b.tilesX + x/diskTileSize
+	return diskHeaderSize + (tile*diskTileSize+y%diskTileSize)*8
+}
+
+// readRow unpacks row y into row, with 255 for alive cells and 0 for dead ones.
+func (b *DiskBoard) readRow(y int, row []byte) {
+	for x := 0; x < b.Width; x += diskTileSize {
+		word := binary.LittleEndian.Uint64(b.data[b.word(x, y):])
+		end := x + diskTileSize
+		if end > b.Width {
+			end = b.Width
+		}
+		for i := x; i < end; i++ {
+			row[i] = byte(-(word >> uint(i-x) & 1))
+		}
+	}
+}
+
+// writeRow packs row into row y, treating 255 as alive and everything else as dead.
+func (b *DiskBoard) writeRow(y int, row []byte) {
+	for x := 0; x < b.Width; x += diskTileSize {
+		var word uint64
+		end := x + diskTileSize
+		if end > b.Width {
+			end = b.Width
+		}
+		for i := x; i < end; i++ {
+			if row[i] == alive {
+				word |= 1 << uint(i-x)
+			}
+		}
+		binary.LittleEndian.PutUint64(b.data[b.word(x, y):], word)
+	}
+}
+
+// Region returns the cells inside r, which must lie within the board, indexed [y][x] from its top left corner.
+// Only the tiles overlapping r are read from the file.
+func (b *DiskBoard) Region(r image.Rectangle) [][]byte {
+	region := make([][]byte, r.Dy())
+	for y := range region {
+		region[y] = make([]byte, r.Dx())
+		for x := range region[y] {
+			cellX, cellY := r.Min.X+x, r.Min.Y+y
+			word := binary.LittleEndian.Uint64(b.data[b.word(cellX-cellX%diskTileSize, cellY):])
+			region[y][x] = byte(-(word >> uint(cellX%diskTileSize) & 1))
+		}
+	}
+	return region
+}
+
+// AliveCount returns the number of alive cells on the board.
+func (b *DiskBoard) AliveCount() int {
+	count := 0
+	for i := diskHeaderSize; i < len(b.data); i += 8 {
+		count += bits.OnesCount64(binary.LittleEndian.Uint64(b.data[i:]))
+	}
+	return count
+}
+
+// StepDisk writes the next state of src into dst, which must have the same size.
+// The board is streamed through memory one band of diskTileSize rows at a time, with the row above and below
+// the band as a halo, so only a few bands per thread are held in memory at once. The threads take turns at the bands.
+func StepDisk(src, dst *DiskBoard, threads int) error {
+	if src.Width != dst.Width || src.Height != dst.Height {
+		return fmt.Errorf("%w: cannot step a %vx%v board into a %vx%v one", ErrInvalidParams, src.Width, src.Height, dst.Width, dst.Height)
+	}
+	if threads <= 0 {
+		return fmt.Errorf("%w: at least 1 thread is needed, not %v", ErrInvalidParams, threads)
+	}
+	done := make(chan bool, threads)
+	for i := 0; i < threads; i++ {
+		go func(i int) {
+			for band := i; band < src.tilesY; band += threads {
+				stepBand(src, dst, band)
+			}
+			done <- true
+		}(i)
+	}
+	for i := 0; i < threads; i++ {
+		<-done
+	}
+	binary.LittleEndian.PutUint64(dst.data[32:], uint64(src.Turn()+1))
+	return nil
+}
+
+// stepBand writes the next state of one band of rows of src into dst, wrapping around the edges of the board.
+func stepBand(src, dst *DiskBoard, band int) {
+	startY := band * diskTileSize
+	endY := startY + diskTileSize
+	if endY > src.Height {
+		endY = src.Height
+	}
+	// The band is read with a border of one cell all around it, in the form calculateNextTile evolves.
+	world := make([][]byte, endY-startY+2)
+	for i := range world {
+		world[i] = make([]byte, src.Width+2)
+		row := world[i][1 : src.Width+1]
+		src.readRow(mod(startY+i-1, src.Height), row)
+		world[i][0], world[i][src.Width+1] = row[src.Width-1], row[0]
+	}
+	for i, row := range calculateNextTile(world) {
+		dst.writeRow(startY+i, row)
+	}
+}
+
+// ImportPgm creates a board at boardPath from the pgm image at pgmPath, reading the image row by row.
+func ImportPgm(pgmPath, boardPath string) (*DiskBoard, error) {
+	file, err := os.Open(pgmPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	in := bufio.NewReader(file)
+	width, height, err := util.ReadPgmHeader(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v: %v", ErrBadImage, pgmPath, err)
+	}
+
+	b, err := CreateDiskBoard(boardPath, width, height)
+	if err != nil {
+		return nil, err
+	}
+	row := make([]byte, width)
+	for y := 0; y < height; y++ {
+		if _, err := io.ReadFull(in, row); err != nil {
+			b.Close()
+			return nil, fmt.Errorf("%w: %v: row %v: %v", ErrBadImage, pgmPath, y, err)
+		}
+		b.writeRow(y, row)
+	}
+
+	fmt.Println("File", filepath.Base(pgmPath), "input done!")
+	return b, nil
+}
+
+// ExportPgm writes the board to a pgm image at path row by row, creating its directory if needed.
+func (b *DiskBoard) ExportPgm(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, ioError := os.Create(path)
+	if ioError != nil {
+		return ioError
+	}
+	defer file.Close()
+
+	out := bufio.NewWriter(file)
+	header := "P5\n" + strconv.Itoa(b.Width) + " " + strconv.Itoa(b.Height) + "\n" + strconv.Itoa(255) + "\n"
+	if _, ioError = out.WriteString(header); ioError != nil {
+		return ioError
+	}
+	row := make([]byte, b.Width)
+	for y := 0; y < b.Height; y++ {
+		b.readRow(y, row)
+		if _, ioError = out.Write(row); ioError != nil {
+			return ioError
+		}
+	}
+	if ioError = out.Flush(); ioError != nil {
+		return ioError
+	}
+
+	if ioError = file.Sync(); ioError != nil {
+		return ioError
+	}
+
+	fmt.Println("File", filepath.Base(path), "output done!")
+	return nil
+}
+
+// RunDisk evolves the board at path for p.Turns more turns with p.Threads threads, without holding it in memory.
+// If there is no board at path it is imported from the image in imageDir matching the params first.
+// The evolved board replaces the one at path, and is also written to a pgm image in outDir like Simulation.Save,
+// named after the turns the board has been evolved for in total.
+func RunDisk(p Params, path, imageDir, outDir string) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	board, err := OpenDiskBoard(path)
+	if os.IsNotExist(err) {
+		imagePath := filepath.Join(imageDir, strconv.Itoa(p.ImageWidth)+"x"+strconv.Itoa(p.ImageHeight)+".pgm")
+		board, err = ImportPgm(imagePath, path)
+	}
+	if err != nil {
+		return err
+	}
+	if board.Width != p.ImageWidth || board.Height != p.ImageHeight {
+		board.Close()
+		return fmt.Errorf("%w: %v is %vx%v, expected %vx%v", ErrBadDiskBoard, path, board.Width, board.Height, p.ImageWidth, p.ImageHeight)
+	}
+
+	// The turns alternate between the board and a scratch board next to it.
+	scratchPath := path + ".next"
+	scratch, err := CreateDiskBoard(scratchPath, board.Width, board.Height)
+	if err != nil {
+		board.Close()
+		return err
+	}
+	// The scratch board is as large as the board, so it is not left behind when the run fails.
+	failed := true
+	defer func() {
+		if failed {
+			os.Remove(scratchPath)
+		}
+	}()
+	for i := 0; i < p.Turns; i++ {
+		if err := StepDisk(board, scratch, p.Threads); err != nil {
+			board.Close()
+			scratch.Close()
+			return err
+		}
+		board, scratch = scratch, board
+		fmt.Println("Completed Turns", board.Turn())
+	}
+
+	filename := strconv.Itoa(board.Width) + "x" + strconv.Itoa(board.Height) + "x" + strconv.Itoa(board.Turn())
+	err = board.ExportPgm(filepath.Join(outDir, filename+".pgm"))
+	fmt.Println("Alive Cells", board.AliveCount())
+	if closeErr := board.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := scratch.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if p.Turns%2 == 1 {
+		// The last turn was written to the scratch board.
+		if err := os.Rename(scratchPath, path); err != nil {
+			return err
+		}
+		failed = false
+		return nil
+	}
+	failed = false
+	return os.Remove(scratchPath)
+}
@@ -0,0 +1,351 @@
+package gol
+
+import (
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+const alive = 255
+const dead = 0
+
+func mod(x, m int) int {
+	return (x + m) % m
+}
+
+// Returns the number of alive neighbours of a given pixel.
+func calculateNeighbours(p Params, x, y int, world [][]byte) int {
+	neighbours := 0
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if i != 0 || j != 0 {
+				if world[mod(y+i, p.ImageHeight)][mod(x+j, p.ImageWidth)] == alive {
+					neighbours++
+				}
+			}
+		}
+	}
+	return neighbours
+}
+
+// Calculates the next state of the cells from startX, startY up to endX, endY of a given board, writing it into newWorld.
+// When record is set, the cells that flipped are appended to flipped, which is returned along with whether any cell flipped.
+func calculateNextState(startX, startY, endX, endY int, p Params, world, newWorld [][]byte, flipped []util.Cell, record bool) ([]util.Cell, bool) {
+	changed := false
+	for y := startY; y < endY; y++ {
+		for x := startX; x < endX; x++ {
+			neighbours := calculateNeighbours(p, x, y, world)
+			if neighbours == 3 || neighbours == 2 && world[y][x] == alive {
+				newWorld[y][x] = alive
+			} else {
+				newWorld[y][x] = dead
+			}
+			if newWorld[y][x] != world[y][x] {
+				changed = true
+				if record {
+					flipped = append(flipped, util.Cell{X: x, Y: y})
+				}
+			}
+		}
+	}
+	return flipped, changed
+}
+
+// Calculates the number of alive cells from a given board.
+func calculateAliveCells(p Params, world [][]byte) []util.Cell {
+	aliveCells := []util.Cell{}
+
+	for y := 0; y < p.ImageHeight; y++ {
+		for x := 0; x < p.ImageWidth; x++ {
+			if world[y][x] == alive {
+				aliveCells = append(aliveCells, util.Cell{X: x, Y: y})
+			}
+		}
+	}
+	return aliveCells
+}
+
+// Returns the part of 0 to size given to worker i of n, so that the parts differ in size by at most one.
+// When there are more workers than there is to split, some of them are given an empty part.
+func split(size, n, i int) (start, end int) {
+	return i * size / n, (i + 1) * size / n
+}
+
+// workerPool is a set of long-lived worker goroutines, each evolving the same tiles of the board every turn.
+// The board is double buffered: the workers read world and write next, which are swapped once every worker is done,
+// so turns do not allocate new boards or copy them back.
+// Only the active tiles are evolved. Every other tile is the same in both buffers, as it did not change last turn.
+type workerPool struct {
+	p     Params
+	world [][]byte
+	next  [][]byte
+	tiles tiling
+	// active marks the tiles that are evolved this turn, and changed the ones in which a cell flipped.
+	active  []bool
+	changed []bool
+	// start tells each worker to evolve its tiles, and whether to record the cells that flip.
+	start []chan bool
+	done  chan bool
+	// flipped holds the cells each worker found flipped on the last turn, reused between turns.
+	flipped [][]util.Cell
+}
+
+// Creates a pool of p.Threads workers for an empty board of the size in p.
+func newWorkerPool(p Params) *workerPool {
+	tiles := newTiling(p.ImageWidth, p.ImageHeight)
+	pool := &workerPool{
+		p:       p,
+		world:   newBoard(p.ImageWidth, p.ImageHeight),
+		next:    newBoard(p.ImageWidth, p.ImageHeight),
+		tiles:   tiles,
+		active:  make([]bool, tiles.count()),
+		changed: make([]bool, tiles.count()),
+		start:   make([]chan bool, p.Threads),
+		done:    make(chan bool, p.Threads),
+		flipped: make([][]util.Cell, p.Threads),
+	}
+	activateAll(pool.active)
+	for i := range pool.start {
+		pool.start[i] = make(chan bool, 1)
+		first, last := split(tiles.count(), p.Threads, i)
+		go pool.worker(i, first, last)
+	}
+	return pool
+}
+
+// Worker goroutine which evolves the active tiles from first up to last every time it is told to start a turn,
+// until the pool is stopped.
+func (pool *workerPool) worker(i, first, last int) {
+	for record := range pool.start[i] {
+		pool.flipped[i] = pool.flipped[i][:0]
+		for tile := first; tile < last; tile++ {
+			if !pool.active[tile] {
+				pool.changed[tile] = false
+				continue
+			}
+			startX, startY, endX, endY := pool.tiles.bounds(tile)
+			pool.flipped[i], pool.changed[tile] = calculateNextState(startX, startY, endX, endY, pool.p, pool.world, pool.next, pool.flipped[i], record)
+		}
+		pool.done <- true
+	}
+}
+
+// Advances the board by one turn using the workers, and returns the cells that flipped if record is set.
+// Waiting for every worker to be done acts as a barrier between turns.
+func (pool *workerPool) advance(record bool) []util.Cell {
+	for _, start := range pool.start {
+		start <- record
+	}
+	for range pool.start {
+		<-pool.done
+	}
+	pool.world, pool.next = pool.next, pool.world
+	pool.tiles.spread(pool.changed, pool.active)
+	if !record {
+		return nil
+	}
+	var flipped []util.Cell
+	for _, cells := range pool.flipped {
+		flipped = append(flipped, cells...)
+	}
+	return flipped
+}
+
+// Replaces the board, evolving every tile on the next turn.
+func (pool *workerPool) load(world [][]byte) {
+	for y := range world {
+		copy(pool.world[y], world[y])
+	}
+	activateAll(pool.active)
+}
+
+// Stops every worker of the pool.
+func (pool *workerPool) stop() {
+	for _, start := range pool.start {
+		close(start)
+	}
+}
+
+// Returns a board of dead cells with the given size.
+func newBoard(width, height int) [][]byte {
+	board := make([][]byte, height)
+	for y := range board {
+		board[y] = make([]byte, width)
+	}
+	return board
+}
+
+// Flips every edited cell, then sends a TurnComplete Event so that SDL renders the edited board straight away.
+func applyEdits(sim *Simulation, events chan<- Event, cells []util.Cell) {
+	if err := sim.Flip(cells...); err != nil {
+		// Edits outside of the board are ignored.
+		return
+	}
+	events <- TurnComplete{
+		CompletedTurns: sim.Turn(),
+	}
+}
+
+// Saves the board as a PGM image in the Saving state.
+func saveBoard(sim *Simulation, state *stateMachine) {
+	state.to(sim.Turn(), Saving)
+	_, err := sim.Save()
+	util.Check(err)
+	if sim.Params().Unbounded {
+		// The cropped image does not record where the pattern is, which the pattern file does.
+		_, err = sim.SaveRLE()
+		util.Check(err)
+	}
+}
+
+// Saves the board, moves to the Quitting state, stops the backend and closes the events channel.
+func quit(sim *Simulation, state *stateMachine, events chan<- Event) {
+	saveBoard(sim, state)
+	state.to(sim.Turn(), Quitting)
+	util.Check(sim.Close())
+
+	// Close the channel to stop the SDL goroutine gracefully. Removing may cause deadlock.
+	close(events)
+}
+
+// Saves the board and quits like quit, but also shuts down the system the backend runs on.
+func kill(sim *Simulation, state *stateMachine, events chan<- Event) {
+	saveBoard(sim, state)
+	state.to(sim.Turn(), Quitting)
+	util.Check(sim.Shutdown())
+	close(events)
+}
+
+// Distributor drives a Simulation from the key presses and edits, and sends the Events SDL and the tests expect.
+func distributor(p Params, events chan<- Event, keyPresses <-chan rune, edits <-chan util.Cell) {
+	state := newStateMachine(events)
+	backend, err := newBackend()
+	util.Check(err)
+	sim, err := NewSimulation(WithParams(p), WithEvents(events), WithBackend(backend))
+	util.Check(err)
+
+	// Send all initially alive cells in a single CellsFlipped Event, or one CellFlipped Event each.
+	sim.sendFlipped(0, sim.AliveCells())
+	if p.Unbounded {
+		events <- BoundsChanged{
+			CompletedTurns: 0,
+			Bounds:         sim.Bounds(),
+		}
+	}
+	state.to(0, Executing)
+
+	// Execute all turns of the Game of Life.
+	// Send correct Events when required, e.g. CellFlipped, TurnComplete and FinalTurnComplete.
+	clock := p.clock()
+	ticker := clock.NewTicker(p.reportInterval())
+	defer ticker.Stop()
+	speed := throttle{clock: clock}
+	var counter stepCounter
+	// steps is the number of turns still to be stepped while paused, after a number typed before 'n'.
+	steps := 0
+
+	// handleKey acts on a key press, returning true once the game has quit.
+	handleKey := func(key rune) bool {
+		paused := state.current() == Paused
+		switch key {
+		case 's':
+			saveBoard(sim, state)
+			if paused {
+				state.to(sim.Turn(), Paused)
+			} else {
+				state.to(sim.Turn(), Executing)
+			}
+		case 'q', 'k':
+			if paused {
+				util.Check(sim.Resume())
+			}
+			if key == 'q' {
+				quit(sim, state, events)
+			} else {
+				kill(sim, state, events)
+			}
+			return true
+		case 'p':
+			if paused {
+				// Resuming cancels any turns left to step.
+				steps = 0
+				util.Check(sim.Resume())
+				state.to(sim.Turn(), Executing)
+			} else {
+				util.Check(sim.Pause())
+				state.to(sim.Turn(), Paused)
+			}
+		case '.':
+			// Step a single turn.
+			if paused && sim.Turn() < p.Turns {
+				util.Check(sim.Step(1))
+			}
+		case 'n':
+			// Step the number of turns typed before, at the current rate limit.
+			if paused {
+				steps = counter.take()
+			}
+		default:
+			if !speed.handleKey(key) {
+				counter.handleKey(key)
+			}
+		}
+		return false
+	}
+
+	// A paused game stays in the loop after the last turn, until it is resumed or quit.
+	for (sim.Turn() < p.Turns || state.current() == Paused) && !sim.stopped() {
+		paused := state.current() == Paused
+		if paused && (steps == 0 || sim.Turn() >= p.Turns) {
+			steps = 0
+			// Nothing is stepped until a key is pressed or a cell is edited.
+			select {
+			case key := <-keyPresses:
+				if handleKey(key) {
+					return
+				}
+			case cell := <-edits:
+				applyEdits(sim, events, []util.Cell{cell})
+			}
+			continue
+		}
+		select {
+		case <-ticker.C():
+			events <- AliveCellsCount{sim.Turn(), len(sim.AliveCells())}
+		default:
+			select {
+			case key := <-keyPresses:
+				if handleKey(key) {
+					return
+				}
+			case cell := <-edits:
+				// Cells can only be edited while the game is paused.
+				if paused {
+					applyEdits(sim, events, []util.Cell{cell})
+				}
+			default:
+				// Wait in short sleeps when the turn rate is limited, so key presses are still handled.
+				if wait := speed.wait(); wait > 0 {
+					if wait > 10*time.Millisecond {
+						wait = 10 * time.Millisecond
+					}
+					clock.Sleep(wait)
+					break
+				}
+				speed.started()
+				util.Check(sim.Step(1))
+				if paused {
+					steps--
+				}
+			}
+		}
+	}
+	state.to(sim.Turn(), Finished)
+	events <- FinalTurnComplete{
+		CompletedTurns: sim.Turn(),
+		Alive:          sim.AliveCells(),
+	}
+
+	// Output the state of the board as a PGM image, then quit.
+	quit(sim, state, events)
+}
@@ -0,0 +1,418 @@
+package gol
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+	"sync"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+//pauseLease is how long a pause lasts without being renewed, so that a controller that goes away while paused
+//does not keep every other controller waiting
+const pauseLease = 10 * time.Second
+
+//Engine holds the world that is evolved and the workers that are connected to it
+type Engine struct {
+	//list of the workers that are connected to the engine
+	workersList []string
+	clients     []*rpc.Client
+	//the world with every paramether it has
+	world  [][]byte
+	width  int
+	height int
+	turns  int
+	//the tiles of the world, and which of them have to be evolved next turn
+	tiles  tiling
+	active []bool
+	//lock chan is used as a lock to avoid race conditions
+	lock chan bool
+	//pausedBy is the client that holds the lock through Pause, or "" if the engine is not paused
+	//it is guarded by mu, as it is read by calls that do not hold the lock
+	mu       sync.Mutex
+	pausedBy string
+	//the pause is released when leaseTimer fires, unless calls from the client that paused are still running
+	//every call from that client and every RenewPause starts the lease again
+	lease      time.Duration
+	leaseTimer *time.Timer
+	calls      int
+	//dial connects to a worker, exit is called once the system has been closed
+	dial func(address string) (*rpc.Client, error)
+	exit func()
+}
+
+//creates an engine without any workers, connecting to them over tcp
+func NewEngine() *Engine {
+	return &Engine{
+		lock:  make(chan bool, 1),
+		lease: pauseLease,
+		dial: func(address string) (*rpc.Client, error) {
+			return rpc.Dial("tcp", address)
+		},
+		exit: func() {
+			time.Sleep(2 * time.Second)
+			os.Exit(0)
+		},
+	}
+}
+
+//SetDial replaces how the engine connects to the workers that register with it
+//it is a function rather than a method so that it is not published over rpc
+func SetDial(e *Engine, dial func(address string) (*rpc.Client, error)) {
+	e.dial = dial
+}
+
+//SetPauseLease replaces how long a pause lasts without being renewed, which is 10 seconds by default
+func SetPauseLease(e *Engine, lease time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lease = lease
+}
+
+//SetExit replaces what happens once the system has been closed, which exits the program by default
+func SetExit(e *Engine, exit func()) {
+	e.exit = exit
+}
+
+//ServeEngine publishes the engine's methods on every connection accepted by l, until l is closed
+func ServeEngine(e *Engine, l net.Listener) {
+	server := rpc.NewServer()
+	server.Register(e)
+	server.Accept(l)
+}
+
+//CloseEngine disconnects the engine from all of its workers
+func CloseEngine(e *Engine) {
+	e.lock <- true
+	defer func() { <-e.lock }()
+	for _, client := range e.clients {
+		client.Close()
+	}
+	e.clients = nil
+	e.workersList = nil
+}
+
+//function that will be called as a goroutine that sends tiles of the board to a worker and waits for their next state
+//signals on done that the worker is done, or why it could not finish
+func startWorkers(client *rpc.Client, address string, workerID int, request TilesRequest, report *TilesReport, done chan error) {
+	err := client.Call(CalculateTiles, request, report)
+	if err == nil && !report.Done {
+		err = errors.New("worker did not finish its tiles")
+	}
+	if err == nil && len(report.Tiles) != len(request.Tiles) {
+		err = fmt.Errorf("worker returned %v tiles, expected %v", len(report.Tiles), len(request.Tiles))
+	}
+	for i := 0; err == nil && i < len(request.Tiles); i++ {
+		height, width := len(request.Tiles[i])-2, len(request.Tiles[i][0])-2
+		if len(report.Tiles[i]) != height {
+			err = fmt.Errorf("worker returned tile %v with %v rows, expected %v", i, len(report.Tiles[i]), height)
+		}
+		for _, row := range report.Tiles[i] {
+			if err == nil && len(row) != width {
+				err = fmt.Errorf("worker returned tile %v with %v columns, expected %v", i, len(row), width)
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("worker %v at %v: %w", workerID, address, err)
+	}
+	done <- err
+}
+
+//returns a copy of the cells of a tile with a border of one cell around it, taken from the tiles next to it
+func (e *Engine) tileWithBorder(tile int) [][]byte {
+	startX, startY, endX, endY := e.tiles.bounds(tile)
+	world := make([][]byte, endY-startY+2)
+	for y := range world {
+		world[y] = make([]byte, endX-startX+2)
+		row := e.world[mod(startY+y-1, e.height)]
+		for x := range world[y] {
+			world[y][x] = row[mod(startX+x-1, e.width)]
+		}
+	}
+	return world
+}
+
+//evolves the world by one turn and returns the cells that flipped, the caller must hold the lock
+//only the active tiles are sent to the workers, split evenly between them, so settled parts of the world cost nothing
+//if a worker fails the world is left as it was
+func (e *Engine) evolve() ([]util.Cell, error) {
+	var tiles []int
+	for tile, active := range e.active {
+		if active {
+			tiles = append(tiles, tile)
+		}
+	}
+	n := len(e.clients)
+	done := make([]chan error, n)
+	reports := make([]TilesReport, n)
+	for i := 0; i < n; i++ {
+		done[i] = make(chan error, 1)
+		//when there are more workers than active tiles, the workers left without any are not called
+		first, last := split(len(tiles), n, i)
+		if first == last {
+			done[i] <- nil
+			continue
+		}
+		request := TilesRequest{}
+		for _, tile := range tiles[first:last] {
+			request.Tiles = append(request.Tiles, e.tileWithBorder(tile))
+		}
+		go startWorkers(e.clients[i], e.workersList[i], i, request, &reports[i], done[i])
+	}
+
+	//checks if all workers are done
+	var err error
+	for i := 0; i < n; i++ {
+		if werr := <-done[i]; werr != nil && err == nil {
+			err = werr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	//writes the tiles back, finding the cells that flipped and the tiles that changed
+	var flipped []util.Cell
+	changed := make([]bool, len(e.active))
+	for i := 0; i < n; i++ {
+		first, last := split(len(tiles), n, i)
+		for j, tile := range tiles[first:last] {
+			startX, startY, endX, endY := e.tiles.bounds(tile)
+			for y := startY; y < endY; y++ {
+				for x := startX; x < endX; x++ {
+					if cell := reports[i].Tiles[j][y-startY][x-startX]; cell != e.world[y][x] {
+						e.world[y][x] = cell
+						changed[tile] = true
+						flipped = append(flipped, util.Cell{X: x, Y: y})
+					}
+				}
+			}
+		}
+	}
+	e.tiles.spread(changed, e.active)
+	e.turns++
+	return flipped, nil
+}
+
+//takes the lock unless the simulation was paused by the same client, in which case Pause already holds it for them
+//every other client waits until the engine is unpaused, returns the function that releases the lock again
+func (e *Engine) acquire(client string) func() {
+	e.mu.Lock()
+	if client != "" && e.pausedBy == client {
+		//the pause cannot run out while the call is running
+		e.calls++
+		e.mu.Unlock()
+		return func() {
+			e.mu.Lock()
+			e.calls--
+			e.leaseTimer.Reset(e.lease)
+			e.mu.Unlock()
+		}
+	}
+	e.mu.Unlock()
+	e.lock <- true
+	return func() { <-e.lock }
+}
+
+//replaces the world that is evolved and resets the number of turns
+func (e *Engine) Load(req LoadRequest, res *bool) (err error) {
+	defer e.acquire(req.Client)()
+	if len(e.clients) == 0 {
+		return errors.New("no available workers")
+	}
+	e.world = make([][]byte, req.ImageHeight)
+	for i := range e.world {
+		e.world[i] = make([]byte, req.ImageWidth)
+		copy(e.world[i], req.World[i])
+	}
+	e.height = req.ImageHeight
+	e.width = req.ImageWidth
+	e.tiles = newTiling(e.width, e.height)
+	e.active = make([]bool, e.tiles.count())
+	activateAll(e.active)
+	e.turns = 0
+	*res = true
+	return nil
+}
+
+//evolves the given number of turns and returns the cells that differ from the world before them
+func (e *Engine) Advance(req AdvanceRequest, res *AdvanceReport) (err error) {
+	defer e.acquire(req.Client)()
+	if len(e.clients) == 0 {
+		return errors.New("no available workers")
+	}
+	if e.world == nil {
+		return errors.New("no world has been loaded")
+	}
+	if req.Turns == 1 {
+		res.Flipped, err = e.evolve()
+		res.Turns = e.turns
+		return err
+	}
+	before := make([][]byte, e.height)
+	for y := range before {
+		before[y] = append([]byte(nil), e.world[y]...)
+	}
+	for i := 0; i < req.Turns && err == nil; i++ {
+		_, err = e.evolve()
+	}
+	res.Flipped = res.Flipped[:0]
+	for y := range e.world {
+		for x := range e.world[y] {
+			if e.world[y][x] != before[y][x] {
+				res.Flipped = append(res.Flipped, util.Cell{X: x, Y: y})
+			}
+		}
+	}
+	res.Turns = e.turns
+	return err
+}
+
+//close every worker connected to the engine then close the engine itself
+func (e *Engine) CloseSystem(req bool, res *bool) (err error) {
+	fmt.Println("Closing the system...")
+	for i, client := range e.clients {
+		fmt.Println("Closing", i)
+		var x bool
+		err2 := client.Call(CloseWorker, true, &x)
+		if err2 != nil {
+			fmt.Println(err2)
+			return err2
+		}
+	}
+	go e.exit()
+	return nil
+}
+
+//register a worker by saving its IP and a poiter: *rpc.Client
+func (e *Engine) Register(req RegisterWorker, res *StatusReport) (err error) {
+	client, err := e.dial(req.WorkerAddres)
+	if err != nil {
+		return err
+	}
+	e.lock <- true
+	e.workersList = append(e.workersList, req.WorkerAddres)
+	e.clients = append(e.clients, client)
+	<-e.lock
+	res.Turns = 0
+	fmt.Println("Worker registered.", req.WorkerAddres)
+	return nil
+}
+
+//The pause and unpause functions take advantage of the lock that is used to avoid race conditions, locking
+//the world so that only the paused controller can change it, req is the client pausing or unpausing
+func (e *Engine) Unpause(req string, res *bool) (err error) {
+	e.mu.Lock()
+	if req == "" || e.pausedBy != req {
+		e.mu.Unlock()
+		return errors.New("the engine was not paused by this client")
+	}
+	e.pausedBy = ""
+	e.leaseTimer.Stop()
+	e.leaseTimer = nil
+	e.mu.Unlock()
+	<-e.lock
+	return nil
+}
+
+//pausing again from the client that already paused the engine does nothing, as it already holds the lock
+//the pause has to be renewed with RenewPause within the lease it reports, or it is released
+func (e *Engine) Pause(req string, res *PauseReport) (err error) {
+	if req == "" {
+		return errors.New("a client is needed to pause the engine")
+	}
+	e.mu.Lock()
+	if e.pausedBy == req {
+		e.leaseTimer.Reset(e.lease)
+		res.Turns, res.Lease = e.turns, e.lease
+		e.mu.Unlock()
+		return nil
+	}
+	e.mu.Unlock()
+	e.lock <- true
+	e.mu.Lock()
+	e.pausedBy = req
+	var timer *time.Timer
+	timer = time.AfterFunc(e.lease, func() {
+		e.expire(timer)
+	})
+	e.leaseTimer = timer
+	res.Turns, res.Lease = e.turns, e.lease
+	e.mu.Unlock()
+	return nil
+}
+
+//keeps the engine paused by the client for another lease
+func (e *Engine) RenewPause(req string, res *bool) (err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if req == "" || e.pausedBy != req {
+		return errors.New("the engine is not paused by this client")
+	}
+	e.leaseTimer.Reset(e.lease)
+	*res = true
+	return nil
+}
+
+//unpauses the engine once the lease of the pause that started timer has run out, unless it has been renewed since
+func (e *Engine) expire(timer *time.Timer) {
+	e.mu.Lock()
+	if e.leaseTimer != timer || e.calls > 0 {
+		e.mu.Unlock()
+		return
+	}
+	e.pausedBy = ""
+	e.leaseTimer = nil
+	e.mu.Unlock()
+	<-e.lock
+}
+
+//returns the state of the board, req is the client asking for it
+func (e *Engine) ReturnBoardState(req string, res *StatusReport) (err error) {
+	defer e.acquire(req)()
+	res.Turns = e.turns
+	res.World = e.world
+	return nil
+}
+
+//starts the listener and prints the IPs
+func Eng(port string) {
+	name, err := os.Hostname()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	addrs, err := net.LookupHost(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var add string
+	for _, a := range addrs {
+		add = a
+	}
+
+	conn, err := net.Dial("udp", "8.8.8.8:80")
+	if err != nil {
+		fmt.Println(err)
+	}
+	localAddr := conn.LocalAddr().(*net.UDPAddr)
+	conn.Close()
+
+	fmt.Println("Engine local address: ", add+":"+port)
+	fmt.Println("Engine internet address: ", localAddr.IP.To16().String()+":"+port)
+	engineListener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer engineListener.Close()
+	ServeEngine(NewEngine(), engineListener)
+}
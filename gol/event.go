@@ -2,6 +2,7 @@ package gol
 
 import (
 	"fmt"
+	"image"
 
 	"uk.ac.bris.cs/gameoflife/util"
 )
@@ -36,10 +37,13 @@ const (
 	Paused State = iota
 	Executing
 	Quitting
+	Loading
+	Saving
+	Finished
 )
 
 // StateChange is an Event notifying the user about the change of state of execution.
-// This Event should be sent every time the execution is paused, resumed or quit.
+// This Event should be sent every time the execution is paused, resumed, saved, finished or quit.
 type StateChange struct { // implements Event
 	CompletedTurns int
 	NewState       State
@@ -53,13 +57,44 @@ type CellFlipped struct { // implements Event
 	Cell           util.Cell
 }
 
+// CellsFlipped is an Event notifying the GUI about all the cells that changed state during a turn.
+// It is sent instead of one CellFlipped Event per cell, which is too slow for large boards,
+// unless Params.PerCellEvents is set.
+// When the image is loaded in, it contains all the cells that are alive.
+type CellsFlipped struct { // implements Event
+	CompletedTurns int
+	Cells          []util.Cell
+}
+
 // TurnComplete is an Event notifying the GUI about turn completion.
 // SDL will render a frame when this event is sent.
-// All CellFlipped events must be sent *before* TurnComplete.
+// All CellFlipped and CellsFlipped events must be sent *before* TurnComplete.
 type TurnComplete struct { // implements Event
 	CompletedTurns int
 }
 
+// BoundsChanged is an Event notifying the GUI that the region of an unbounded board with alive cells in it has changed.
+// It is sent after the CellsFlipped or CellFlipped Events that changed it, and Bounds is empty if every cell is dead.
+type BoundsChanged struct { // implements Event
+	CompletedTurns int
+	Bounds         image.Rectangle
+}
+
+// Stabilised is an Event notifying the user that the board has started repeating itself, once CompletedTurns turns
+// have been completed. Period is the number of turns after which it repeats, 1 if it no longer changes.
+// It is sent once, when the repeat is first found, and again if the board repeats itself after being edited.
+type Stabilised struct { // implements Event
+	CompletedTurns int
+	Period         int
+}
+
+// KeyPressed is an Event notifying the user that a key was pressed during a recorded run.
+// It is only sent by Replay, at the point of the run the key was pressed.
+type KeyPressed struct { // implements Event
+	CompletedTurns int
+	Key            rune
+}
+
 // FinalTurnComplete is an Event notifying the testing framework about the new world state after execution finished.
 // The data included with this Event is used directly by the tests.
 // SDL ignores this Event.
@@ -78,6 +113,12 @@ func (state State) String() string {
 		return "Executing"
 	case Quitting:
 		return "Quitting"
+	case Loading:
+		return "Loading"
+	case Saving:
+		return "Saving"
+	case Finished:
+		return "Finished"
 	default:
 		return "Incorrect State"
 	}
@@ -115,6 +156,14 @@ func (event CellFlipped) GetCompletedTurns() int {
 	return event.CompletedTurns
 }
 
+func (event CellsFlipped) String() string {
+	return fmt.Sprintf("")
+}
+
+func (event CellsFlipped) GetCompletedTurns() int {
+	return event.CompletedTurns
+}
+
 func (event TurnComplete) String() string {
 	return fmt.Sprintf("")
 }
@@ -123,6 +172,33 @@ func (event TurnComplete) GetCompletedTurns() int {
 	return event.CompletedTurns
 }
 
+func (event BoundsChanged) String() string {
+	return fmt.Sprintf("")
+}
+
+func (event BoundsChanged) GetCompletedTurns() int {
+	return event.CompletedTurns
+}
+
+func (event Stabilised) String() string {
+	if event.Period == 1 {
+		return "Stabilised"
+	}
+	return fmt.Sprintf("Stabilised with period %v", event.Period)
+}
+
+func (event Stabilised) GetCompletedTurns() int {
+	return event.CompletedTurns
+}
+
+func (event KeyPressed) String() string {
+	return fmt.Sprintf("Key %q pressed", event.Key)
+}
+
+func (event KeyPressed) GetCompletedTurns() int {
+	return event.CompletedTurns
+}
+
 func (event FinalTurnComplete) String() string {
 	return fmt.Sprintf("")
 }
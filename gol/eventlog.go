@@ -0,0 +1,606 @@
+package gol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// ErrBadEventLog is returned when data is not an event log written by an EventRecorder.
+var ErrBadEventLog = errors.New("gol: bad event log")
+
+// eventLogMagic starts every event log. It is followed by eventLogVersion, then the width, height and turns
+// of the params the run was started with, and a byte that is 1 if the board was unbounded, all as uvarints.
+var eventLogMagic = [8]byte{'G', 'O', 'L', 'E', 'V', 'L', 'O', 'G'}
+
+const eventLogVersion = 1
+
+// The kinds of record in an event log. Every record is its kind, then the completed turns of its Event as a uvarint,
+// then the fields of the Event. Numbers are varints, and lists of cells are a count followed by the
+// difference of every cell from the one before it, starting from 0, 0, which keeps runs of nearby cells small.
+const (
+	recordCellFlipped byte = iota + 1
+	recordCellsFlipped
+	recordTurnComplete
+	recordAliveCellsCount
+	recordImageOutputComplete
+	recordStateChange
+	recordBoundsChanged
+	recordStabilised
+	recordFinalTurnComplete
+	recordKeyPressed
+)
+
+// maxEventLogSize is the largest width or height of a board in an event log,
+// which is as large as a texture SDL can usually draw it into.
+const maxEventLogSize = 16384
+
+// keyframeInterval is the number of frames between the boards an EventLog keeps, so that seeking
+// only has to replay the flips of at most this many turns.
+const keyframeInterval = 256
+
+// EventRecorder writes the Events of a run, and the keys pressed during it, to an event log file
+// that Replay can play back. It sits between a run and whatever renders it, passing everything on unchanged.
+type EventRecorder struct {
+	mu   sync.Mutex
+	file *os.File
+	out  *bufio.Writer
+	// record is reused for every record written.
+	record []byte
+	// turn is the number of turns completed by the last Event, which key presses are recorded at.
+	turn   int
+	closed bool
+	// done is closed along with the log, which stops passing on key presses.
+	done chan struct{}
+	err  error
+}
+
+// CreateEventLog creates an event log file at path for a run with the given params, replacing any file there.
+func CreateEventLog(path string, p Params) (*EventRecorder, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &EventRecorder{
+		file: file,
+		out:  bufio.NewWriterSize(file, 64*1024),
+		done: make(chan struct{}),
+	}
+	header := append([]byte(nil), eventLogMagic[:]...)
+	header = appendUvarint(header, eventLogVersion)
+	header = appendUvarint(header, uint64(p.ImageWidth))
+	header = appendUvarint(header, uint64(p.ImageHeight))
+	header = appendUvarint(header, uint64(p.Turns))
+	if p.Unbounded {
+		header = append(header, 1)
+	} else {
+		header = append(header, 0)
+	}
+	if _, err := r.out.Write(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Events records every Event received on events and passes it on to the returned channel.
+// Once events is closed the log is written out and closed, then the returned channel is closed.
+func (r *EventRecorder) Events(events <-chan Event) <-chan Event {
+	out := make(chan Event, cap(events))
+	go func() {
+		for event := range events {
+			r.write(event)
+			out <- event
+		}
+		r.close()
+		close(out)
+	}()
+	return out
+}
+
+// KeyPresses records every key sent on the returned channel and passes it on to keyPresses.
+// Once the events passed to Events have ended and the log has been closed, keys are no longer recorded or passed on,
+// as the run they were for has finished.
+func (r *EventRecorder) KeyPresses(keyPresses chan<- rune) chan<- rune {
+	in := make(chan rune, cap(keyPresses))
+	go func() {
+		for {
+			select {
+			case key := <-in:
+				r.mu.Lock()
+				turn := r.turn
+				r.mu.Unlock()
+				r.write(KeyPressed{CompletedTurns: turn, Key: key})
+				select {
+				case keyPresses <- key:
+				case <-r.done:
+					return
+				}
+			case <-r.done:
+				return
+			}
+		}
+	}()
+	return in
+}
+
+// Err returns the first error met while writing the log. It is complete once the channel returned by Events is closed.
+func (r *EventRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// write appends the record of an Event to the log. Events that have no record are not written.
+func (r *EventRecorder) write(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.err != nil {
+		return
+	}
+	record, ok := appendRecord(r.record[:0], event)
+	if !ok {
+		return
+	}
+	r.record = record
+	if _, ok := event.(KeyPressed); !ok {
+		r.turn = event.GetCompletedTurns()
+	}
+	_, r.err = r.out.Write(record)
+}
+
+// close writes out the buffered records and closes the file.
+func (r *EventRecorder) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	close(r.done)
+	if err := r.out.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// appendRecord appends the record of an Event to b, returning false if it is not an Event that is recorded.
+func appendRecord(b []byte, event Event) ([]byte, bool) {
+	var kind byte
+	switch event.(type) {
+	case CellFlipped:
+		kind = recordCellFlipped
+	case CellsFlipped:
+		kind = recordCellsFlipped
+	case TurnComplete:
+		kind = recordTurnComplete
+	case AliveCellsCount:
+		kind = recordAliveCellsCount
+	case ImageOutputComplete:
+		kind = recordImageOutputComplete
+	case StateChange:
+		kind = recordStateChange
+	case BoundsChanged:
+		kind = recordBoundsChanged
+	case Stabilised:
+		kind = recordStabilised
+	case FinalTurnComplete:
+		kind = recordFinalTurnComplete
+	case KeyPressed:
+		kind = recordKeyPressed
+	default:
+		return b, false
+	}
+	b = append(b, kind)
+	b = appendUvarint(b, uint64(event.GetCompletedTurns()))
+	switch e := event.(type) {
+	case CellFlipped:
+		b = appendVarint(b, int64(e.Cell.X))
+		b = appendVarint(b, int64(e.Cell.Y))
+	case CellsFlipped:
+		b = appendCells(b, e.Cells)
+	case AliveCellsCount:
+		b = appendUvarint(b, uint64(e.CellsCount))
+	case ImageOutputComplete:
+		b = appendUvarint(b, uint64(len(e.Filename)))
+		b = append(b, e.Filename...)
+	case StateChange:
+		b = appendUvarint(b, uint64(e.NewState))
+	case BoundsChanged:
+		for _, v := range []int{e.Bounds.Min.X, e.Bounds.Min.Y, e.Bounds.Max.X, e.Bounds.Max.Y} {
+			b = appendVarint(b, int64(v))
+		}
+	case Stabilised:
+		b = appendUvarint(b, uint64(e.Period))
+	case FinalTurnComplete:
+		b = appendCells(b, e.Alive)
+	case KeyPressed:
+		b = appendUvarint(b, uint64(e.Key))
+	}
+	return b, true
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+// appendCells appends a count of cells, then each cell as its difference from the one before.
+func appendCells(b []byte, cells []util.Cell) []byte {
+	b = appendUvarint(b, uint64(len(cells)))
+	var last util.Cell
+	for _, cell := range cells {
+		b = appendVarint(b, int64(cell.X-last.X))
+		b = appendVarint(b, int64(cell.Y-last.Y))
+		last = cell
+	}
+	return b
+}
+
+// logReader decodes the records of an event log, remembering the first error it meets.
+type logReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *logReader) fail(what string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: bad %v at byte %v", ErrBadEventLog, what, r.pos)
+	}
+}
+
+func (r *logReader) uvarint(what string) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail(what)
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *logReader) varint(what string) int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail(what)
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+// number reads a uvarint that must be from min up to max.
+func (r *logReader) number(what string, min, max uint64) int {
+	v := r.uvarint(what)
+	if r.err == nil && (v < min || v > max) {
+		r.err = fmt.Errorf("%w: %v %v is not from %v to %v", ErrBadEventLog, what, v, min, max)
+	}
+	if r.err != nil {
+		return 0
+	}
+	return int(v)
+}
+
+// count reads a uvarint that counts things taking at least one byte each, so it cannot be more than the bytes left.
+func (r *logReader) count(what string) int {
+	v := r.uvarint(what)
+	if v > uint64(len(r.data)-r.pos) {
+		r.fail(what)
+		return 0
+	}
+	return int(v)
+}
+
+func (r *logReader) cells() []util.Cell {
+	cells := make([]util.Cell, r.count("cell count"))
+	var last util.Cell
+	for i := range cells {
+		last.X += int(r.varint("cell"))
+		last.Y += int(r.varint("cell"))
+		cells[i] = last
+	}
+	return cells
+}
+
+// event decodes the next record into its Event.
+func (r *logReader) event() Event {
+	start := r.pos
+	kind := r.data[r.pos]
+	r.pos++
+	turn := r.number("turn", 0, math.MaxInt32)
+	var event Event
+	switch kind {
+	case recordCellFlipped:
+		x := r.varint("cell")
+		event = CellFlipped{turn, util.Cell{X: int(x), Y: int(r.varint("cell"))}}
+	case recordCellsFlipped:
+		event = CellsFlipped{turn, r.cells()}
+	case recordTurnComplete:
+		event = TurnComplete{turn}
+	case recordAliveCellsCount:
+		event = AliveCellsCount{turn, int(r.uvarint("count"))}
+	case recordImageOutputComplete:
+		n := r.count("file name")
+		event = ImageOutputComplete{turn, string(r.data[r.pos : r.pos+n])}
+		r.pos += n
+	case recordStateChange:
+		event = StateChange{turn, State(r.uvarint("state"))}
+	case recordBoundsChanged:
+		var v [4]int
+		for i := range v {
+			v[i] = int(r.varint("bounds"))
+		}
+		event = BoundsChanged{turn, image.Rect(v[0], v[1], v[2], v[3])}
+	case recordStabilised:
+		event = Stabilised{turn, int(r.uvarint("period"))}
+	case recordFinalTurnComplete:
+		event = FinalTurnComplete{turn, r.cells()}
+	case recordKeyPressed:
+		event = KeyPressed{turn, rune(r.uvarint("key"))}
+	default:
+		r.pos = start
+		r.fail("record kind")
+	}
+	if r.err != nil {
+		return nil
+	}
+	return event
+}
+
+// EventLog is an event log read by OpenEventLog, split into frames that each end with a TurnComplete Event.
+// The first frame only holds the initial board, the CellsFlipped Event sent before any turn is complete,
+// so that the board going to turn 0 shows is the one the run started from.
+// The records are kept encoded, and only decoded as they are played.
+type EventLog struct {
+	// Params holds the size, turns and unboundedness of the recorded run. Its Clock drives Replay.
+	Params Params
+	data   []byte
+	// frames holds the position of the start of every frame. Frame 0 starts at the first record,
+	// and the last frame holds whatever follows the last TurnComplete.
+	frames []frame
+	// keyframes holds the alive cells and bounds at the start of every keyframeInterval-th frame.
+	keyframes []keyframe
+}
+
+// frame is the start of a frame of an EventLog, and the turns completed at that point.
+type frame struct {
+	pos  int
+	turn int
+}
+
+// keyframe is the board at the start of a frame, as sorted alive cells.
+type keyframe struct {
+	cells  []util.Cell
+	bounds image.Rectangle
+}
+
+// OpenEventLog reads the event log at path.
+func OpenEventLog(path string) (*EventLog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventLog(data)
+}
+
+// ParseEventLog reads an event log from data, checking every record and indexing its frames.
+func ParseEventLog(data []byte) (*EventLog, error) {
+	if len(data) < len(eventLogMagic) || string(data[:len(eventLogMagic)]) != string(eventLogMagic[:]) {
+		return nil, fmt.Errorf("%w: missing header", ErrBadEventLog)
+	}
+	r := &logReader{data: data, pos: len(eventLogMagic)}
+	if version := r.uvarint("version"); r.err == nil && version != eventLogVersion {
+		return nil, fmt.Errorf("%w: version %v, expected %v", ErrBadEventLog, version, eventLogVersion)
+	}
+	l := &EventLog{data: data}
+	l.Params.ImageWidth = r.number("width", 1, maxEventLogSize)
+	l.Params.ImageHeight = r.number("height", 1, maxEventLogSize)
+	l.Params.Turns = r.number("turns", 0, math.MaxInt32)
+	if r.err == nil && r.pos == len(data) {
+		r.fail("header")
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	l.Params.Unbounded = data[r.pos] == 1
+	r.pos++
+
+	b := newReplayBoard(nil, image.Rectangle{})
+	l.frames = append(l.frames, frame{pos: r.pos})
+	l.keyframes = append(l.keyframes, b.keyframe())
+	initial := false
+	for r.pos < len(data) {
+		event := r.event()
+		if r.err != nil {
+			return nil, r.err
+		}
+		if err := l.checkCells(event); err != nil {
+			return nil, err
+		}
+		b.apply(event)
+		if _, ok := event.(CellsFlipped); ok && len(l.frames) == 1 {
+			initial = true
+		}
+		end := false
+		turn := 0
+		if e, ok := event.(TurnComplete); ok {
+			end, turn = true, e.CompletedTurns
+		} else if initial && (r.pos == len(data) || data[r.pos] != recordBoundsChanged) {
+			// The initial board ends before anything but the bounds of an unbounded one.
+			end = true
+		}
+		if end {
+			initial = false
+			l.frames = append(l.frames, frame{pos: r.pos, turn: turn})
+			if (len(l.frames)-1)%keyframeInterval == 0 {
+				l.keyframes = append(l.keyframes, b.keyframe())
+			}
+		}
+	}
+	return l, nil
+}
+
+// checkCells returns an error if an Event of a bounded log has a cell outside of the board,
+// which a renderer of that size could not draw.
+func (l *EventLog) checkCells(event Event) error {
+	if l.Params.Unbounded {
+		return nil
+	}
+	var cells []util.Cell
+	switch e := event.(type) {
+	case CellFlipped:
+		cells = []util.Cell{e.Cell}
+	case CellsFlipped:
+		cells = e.Cells
+	case FinalTurnComplete:
+		cells = e.Alive
+	}
+	for _, cell := range cells {
+		if cell.X < 0 || cell.X >= l.Params.ImageWidth || cell.Y < 0 || cell.Y >= l.Params.ImageHeight {
+			return fmt.Errorf("%w: cell %v is not on the %vx%v board", ErrBadEventLog, cell, l.Params.ImageWidth, l.Params.ImageHeight)
+		}
+	}
+	return nil
+}
+
+// Turns returns the number of turns completed by the end of the log.
+func (l *EventLog) Turns() int {
+	return l.frames[len(l.frames)-1].turn
+}
+
+// events decodes the Events of a frame.
+func (l *EventLog) events(i int) []Event {
+	end := len(l.data)
+	if i+1 < len(l.frames) {
+		end = l.frames[i+1].pos
+	}
+	// Every record was checked when the log was parsed.
+	r := &logReader{data: l.data[:end], pos: l.frames[i].pos}
+	var events []Event
+	for r.pos < end {
+		events = append(events, r.event())
+	}
+	return events
+}
+
+// board returns the board at the start of a frame, or at the end of the log for the frame after the last,
+// replaying the frames since the keyframe before it.
+func (l *EventLog) board(i int) *replayBoard {
+	k := i / keyframeInterval
+	if k >= len(l.keyframes) {
+		k = len(l.keyframes) - 1
+	}
+	b := newReplayBoard(l.keyframes[k].cells, l.keyframes[k].bounds)
+	for f := k * keyframeInterval; f < i; f++ {
+		for _, event := range l.events(f) {
+			b.apply(event)
+		}
+	}
+	return b
+}
+
+// frameAt returns the last frame that starts at or before the given turn, or frame 0 if there is none.
+func (l *EventLog) frameAt(turn int) int {
+	return sort.Search(len(l.frames)-1, func(i int) bool {
+		return l.frames[i+1].turn > turn
+	})
+}
+
+// replayBoard is the board a renderer shows after the Events played so far: the cells flipped an odd number of times,
+// and the last bounds an unbounded board was reported to have.
+type replayBoard struct {
+	alive  map[util.Cell]bool
+	bounds image.Rectangle
+}
+
+func newReplayBoard(cells []util.Cell, bounds image.Rectangle) *replayBoard {
+	b := &replayBoard{alive: make(map[util.Cell]bool, len(cells)), bounds: bounds}
+	for _, cell := range cells {
+		b.alive[cell] = true
+	}
+	return b
+}
+
+// apply flips the cells of a CellFlipped or CellsFlipped Event, and records the bounds of a BoundsChanged one.
+func (b *replayBoard) apply(event Event) {
+	switch e := event.(type) {
+	case CellFlipped:
+		b.flip(e.Cell)
+	case CellsFlipped:
+		for _, cell := range e.Cells {
+			b.flip(cell)
+		}
+	case BoundsChanged:
+		b.bounds = e.Bounds
+	}
+}
+
+func (b *replayBoard) flip(cell util.Cell) {
+	if b.alive[cell] {
+		delete(b.alive, cell)
+	} else {
+		b.alive[cell] = true
+	}
+}
+
+// difference returns the cells that have to be flipped to turn this board into other.
+func (b *replayBoard) difference(other *replayBoard) []util.Cell {
+	var cells []util.Cell
+	for cell := range b.alive {
+		if !other.alive[cell] {
+			cells = append(cells, cell)
+		}
+	}
+	for cell := range other.alive {
+		if !b.alive[cell] {
+			cells = append(cells, cell)
+		}
+	}
+	sortCells(cells)
+	return cells
+}
+
+func (b *replayBoard) keyframe() keyframe {
+	cells := make([]util.Cell, 0, len(b.alive))
+	for cell := range b.alive {
+		cells = append(cells, cell)
+	}
+	sortCells(cells)
+	return keyframe{cells: cells, bounds: b.bounds}
+}
+
+// sortCells sorts cells row by row.
+func sortCells(cells []util.Cell) {
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y != cells[j].Y {
+			return cells[i].Y < cells[j].Y
+		}
+		return cells[i].X < cells[j].X
+	})
+}
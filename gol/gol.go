@@ -0,0 +1,69 @@
+package gol
+
+import (
+	"fmt"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// Rule is the birth/survival rule used to evolve the board, in B/S notation.
+const Rule = "B3/S23"
+
+// Params provides the details of how to run the Game of Life and which image to load.
+type Params struct {
+	Turns       int
+	Threads     int
+	ImageWidth  int
+	ImageHeight int
+	// ReportInterval is how often Run sends an AliveCellsCount Event. Defaults to 2 seconds.
+	ReportInterval time.Duration
+	// Clock drives Run's AliveCellsCount reports and turn rate limit. Defaults to the system clock.
+	Clock Clock
+	// Unbounded evolves the image on an infinite board instead of wrapping it around its edges,
+	// with its top left cell at 0, 0. Unbounded boards are always evolved locally.
+	Unbounded bool
+	// UntilStable stops Run once the board repeats itself, before the number of turns is reached.
+	UntilStable bool
+	// Soup generates the starting board instead of reading it from an image, if it is set.
+	Soup *Soup
+	// Metrics is the path of a file the Metrics of every turn are written to, as CSV if it ends in .csv
+	// or as JSON Lines if it ends in .jsonl. No metrics are recorded if it is empty.
+	Metrics string
+	// PerCellEvents sends a CellFlipped Event for every cell that flips, including the initially alive ones,
+	// instead of a single CellsFlipped Event per turn. It is much slower, but suits renderers that only handle CellFlipped.
+	PerCellEvents bool
+}
+
+// newBackend creates the Backend for every call to Run.
+var newBackend = func() (Backend, error) {
+	return NewLocal(), nil
+}
+
+// UseBackend selects the Backend that Run evolves the board with: "local" uses goroutines
+// and "cluster" uses the engine at engAddr and its workers. The default is "local".
+func UseBackend(name, engAddr string) error {
+	if name != "local" && name != "cluster" {
+		return fmt.Errorf("%w: %q, expected \"local\" or \"cluster\"", ErrUnknownBackend, name)
+	}
+	UseBackendFunc(func() (Backend, error) {
+		return NewBackend(name, engAddr)
+	})
+	return nil
+}
+
+// UseBackendFunc makes Run evolve the board with a Backend created by newBackend, which is called once per Run.
+func UseBackendFunc(newBackendFunc func() (Backend, error)) {
+	newBackend = newBackendFunc
+}
+
+// Run starts the processing of Game of Life. It should initialise channels and goroutines.
+// It is a wrapper around a Simulation driven by key presses, which panics if the image cannot be read or written.
+func Run(p Params, events chan<- Event, keyPresses <-chan rune) {
+	RunWithEdits(p, events, keyPresses, nil)
+}
+
+// RunWithEdits is the same as Run, but additionally flips every cell received on edits while the game is paused.
+func RunWithEdits(p Params, events chan<- Event, keyPresses <-chan rune, edits <-chan util.Cell) {
+	go distributor(p, events, keyPresses, edits)
+}
@@ -0,0 +1,164 @@
+package gol
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// readPgmImage reads the pgm image at path and returns its pixels, checking that it has the given size.
+func readPgmImage(path string, width, height int) ([][]byte, error) {
+	data, ioError := ioutil.ReadFile(path)
+	if ioError != nil {
+		return nil, ioError
+	}
+
+	imageWidth, imageHeight, image, err := util.DecodePgm(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v: %v", ErrBadImage, path, err)
+	}
+	if imageWidth != width {
+		return nil, fmt.Errorf("%w: %v has width %v, expected %v", ErrBadImage, path, imageWidth, width)
+	}
+	if imageHeight != height {
+		return nil, fmt.Errorf("%w: %v has height %v, expected %v", ErrBadImage, path, imageHeight, height)
+	}
+
+	world := make([][]byte, height)
+	for y := range world {
+		world[y] = image[y*width : (y+1)*width : (y+1)*width]
+	}
+
+	fmt.Println("File", filepath.Base(path), "input done!")
+	return world, nil
+}
+
+// writePgmImage writes the world to a pgm image at path, creating its directory if needed.
+// If comment is not empty it is written as a comment line in the header.
+func writePgmImage(path string, world [][]byte, comment string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, ioError := os.Create(path)
+	if ioError != nil {
+		return ioError
+	}
+	defer file.Close()
+
+	height := len(world)
+	width := 0
+	if height > 0 {
+		width = len(world[0])
+	}
+	header := "P5\n"
+	if comment != "" {
+		header += "# " + comment + "\n"
+	}
+	header += strconv.Itoa(width) + " " + strconv.Itoa(height) + "\n" + strconv.Itoa(255) + "\n"
+	if _, ioError = file.WriteString(header); ioError != nil {
+		return ioError
+	}
+	for _, row := range world {
+		if _, ioError = file.Write(row); ioError != nil {
+			return ioError
+		}
+	}
+
+	if ioError = file.Sync(); ioError != nil {
+		return ioError
+	}
+
+	fmt.Println("File", filepath.Base(path), "output done!")
+	return nil
+}
+
+// rleLineLength is the longest line written to a run length encoded pattern.
+const rleLineLength = 70
+
+// writeRLE writes the world to a run length encoded pattern at path, creating its directory if needed.
+// The position of its top left cell and the turn are recorded in a #CXRLE line, and comment in a #C line if it is not empty.
+func writeRLE(path string, world [][]byte, topLeft image.Point, turn int, comment string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, ioError := os.Create(path)
+	if ioError != nil {
+		return ioError
+	}
+	defer file.Close()
+
+	height := len(world)
+	width := 0
+	if height > 0 {
+		width = len(world[0])
+	}
+	out := bufio.NewWriter(file)
+	fmt.Fprintf(out, "#CXRLE Pos=%v,%v Gen=%v\n", topLeft.X, topLeft.Y, turn)
+	if comment != "" {
+		fmt.Fprintf(out, "#C %v\n", comment)
+	}
+	fmt.Fprintf(out, "x = %v, y = %v, rule = %v\n", width, height, Rule)
+
+	// Runs of dead cells at the end of a row, and of empty rows at the end of the pattern, are left out.
+	line := 0
+	write := func(count int, tag byte) {
+		run := string(tag)
+		if count > 1 {
+			run = strconv.Itoa(count) + run
+		}
+		if line+len(run) > rleLineLength {
+			out.WriteByte('\n')
+			line = 0
+		}
+		out.WriteString(run)
+		line += len(run)
+	}
+	emptyRows := 0
+	for _, row := range world {
+		end := len(row)
+		for end > 0 && row[end-1] != alive {
+			end--
+		}
+		if end == 0 {
+			emptyRows++
+			continue
+		}
+		if emptyRows > 0 {
+			write(emptyRows, '$')
+			emptyRows = 0
+		}
+		for x := 0; x < end; {
+			run := x + 1
+			for run < end && (row[run] == alive) == (row[x] == alive) {
+				run++
+			}
+			if row[x] == alive {
+				write(run-x, 'o')
+			} else {
+				write(run-x, 'b')
+			}
+			x = run
+		}
+		emptyRows = 1
+	}
+	write(1, '!')
+	out.WriteByte('\n')
+
+	if ioError = out.Flush(); ioError != nil {
+		return ioError
+	}
+	if ioError = file.Sync(); ioError != nil {
+		return ioError
+	}
+
+	fmt.Println("File", filepath.Base(path), "output done!")
+	return nil
+}
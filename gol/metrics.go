@@ -0,0 +1,163 @@
+package gol
+
+import (
+	"bufio"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// Metrics is the activity of the board over a single turn, which a Simulation records when Params.Metrics is set.
+type Metrics struct {
+	// Turn is the number of turns completed, including this one.
+	Turn   int
+	Alive  int
+	Births int
+	Deaths int
+	// Bounds is the smallest rectangle holding every alive cell, which is empty if every cell is dead.
+	// Cells of a bounded board that wrap around its edges are not treated as next to each other.
+	Bounds image.Rectangle
+	// Duration is how long the backend took to process the turn.
+	Duration time.Duration
+}
+
+// metricsHeader names the columns of a CSV metrics file, which are also the keys of a JSON Lines one.
+var metricsHeader = []string{"turn", "alive", "births", "deaths", "min_x", "min_y", "max_x", "max_y", "duration_ns"}
+
+// metricsRecorder writes the Metrics of every turn to a file. Rather than scanning the board each turn,
+// it keeps the number of alive cells in every row and column up to date from the cells that flip,
+// which works the same for every Backend and only costs a scan of the rows and columns per turn.
+type metricsRecorder struct {
+	file      *os.File
+	out       *bufio.Writer
+	jsonLines bool
+	// line is reused for every line written, so recording a turn does not allocate.
+	line  []byte
+	alive int
+	// rows and cols count the alive cells of a bounded board. They are nil for an unbounded board,
+	// whose bounds the Simulation already keeps.
+	rows, cols []int
+}
+
+// validMetricsPath checks that a metrics file has an extension a metricsRecorder can write.
+func validMetricsPath(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".csv" || ext == ".jsonl"
+}
+
+// newMetricsRecorder creates the metrics file at path, as CSV or JSON Lines depending on its extension,
+// for a board with the given alive cells. A width and height of 0 mean the board is unbounded.
+func newMetricsRecorder(path string, width, height int, aliveCells []util.Cell) (*metricsRecorder, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &metricsRecorder{
+		file:      file,
+		out:       bufio.NewWriterSize(file, 64*1024),
+		jsonLines: filepath.Ext(path) == ".jsonl",
+	}
+	if width > 0 && height > 0 {
+		r.rows, r.cols = make([]int, height), make([]int, width)
+	}
+	for _, cell := range aliveCells {
+		r.flip(cell, true)
+	}
+	if !r.jsonLines {
+		for i, column := range metricsHeader {
+			if i > 0 {
+				r.out.WriteByte(',')
+			}
+			r.out.WriteString(column)
+		}
+		r.out.WriteByte('\n')
+	}
+	return r, nil
+}
+
+// flip counts a cell that has just become alive if nowAlive is set, or that has just died otherwise.
+func (r *metricsRecorder) flip(cell util.Cell, nowAlive bool) {
+	change := -1
+	if nowAlive {
+		change = 1
+	}
+	r.alive += change
+	if r.rows != nil {
+		r.rows[cell.Y] += change
+		r.cols[cell.X] += change
+	}
+}
+
+// bounds returns the smallest rectangle holding every alive cell of a bounded board.
+func (r *metricsRecorder) bounds() image.Rectangle {
+	minY, maxY := span(r.rows)
+	minX, maxX := span(r.cols)
+	if minX > maxX || minY > maxY {
+		return image.Rectangle{}
+	}
+	return image.Rect(minX, minY, maxX+1, maxY+1)
+}
+
+// span returns the first and last index of counts that is not 0, with the first after the last if there are none.
+func span(counts []int) (first, last int) {
+	first, last = len(counts), -1
+	for i, count := range counts {
+		if count != 0 {
+			first = i
+			break
+		}
+	}
+	for i := len(counts) - 1; i >= first; i-- {
+		if counts[i] != 0 {
+			last = i
+			break
+		}
+	}
+	return first, last
+}
+
+// record writes the metrics of a turn as the next line of the file.
+func (r *metricsRecorder) record(m Metrics) error {
+	values := [...]int64{int64(m.Turn), int64(m.Alive), int64(m.Births), int64(m.Deaths),
+		int64(m.Bounds.Min.X), int64(m.Bounds.Min.Y), int64(m.Bounds.Max.X), int64(m.Bounds.Max.Y), int64(m.Duration)}
+	line := r.line[:0]
+	if r.jsonLines {
+		line = append(line, '{')
+	}
+	for i, value := range values {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		if r.jsonLines {
+			line = append(line, '"')
+			line = append(line, metricsHeader[i]...)
+			line = append(line, '"', ':')
+		}
+		line = strconv.AppendInt(line, value, 10)
+	}
+	if r.jsonLines {
+		line = append(line, '}')
+	}
+	line = append(line, '\n')
+	r.line = line
+	_, err := r.out.Write(line)
+	return err
+}
+
+// close writes out any buffered lines and closes the file.
+func (r *metricsRecorder) close() error {
+	if err := r.out.Flush(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,28 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package gol
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the first size bytes of file into memory, on systems where it cannot be memory mapped.
+// The whole board is then held in memory, so boards larger than it cannot be used.
+func mapFile(file *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := file.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+// unmapFile writes memory returned by mapFile back to the file.
+func unmapFile(file *os.File, data []byte) error {
+	if data == nil {
+		return nil
+	}
+	_, err := file.WriteAt(data, 0)
+	return err
+}
@@ -0,0 +1,135 @@
+package gol
+
+import (
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// Replay plays an event log back on events, sending the Events of the recorded run again so that SDL or any other
+// renderer shows it without it being recomputed. Playback starts at rate turns per second, or unlimited if it is 0.
+// The keys are those of Run: 'p' pauses and resumes, '[' and ']' halve and double the rate and 'u' removes the limit,
+// while paused '.' plays a single turn, and 'q' or 'k' stop the replay and close events. On top of those,
+// ',' goes back a turn, a number typed before 'g' goes to that turn and a number typed before 'n' skips that many turns.
+// Seeking sends a single CellsFlipped Event turning the board shown into the one at the new turn, then a TurnComplete.
+// Once the end of the log is reached the replay waits for keys, so it can still be sought back. Edits are ignored.
+func Replay(l *EventLog, rate float64, events chan<- Event, keyPresses <-chan rune, edits <-chan util.Cell) {
+	p := &player{
+		log:    l,
+		events: events,
+		speed:  throttle{clock: l.Params.clock(), rate: rate},
+	}
+	go p.run(keyPresses, edits)
+}
+
+// player is the position of a replay in its log, which is always the start of a frame.
+type player struct {
+	log    *EventLog
+	events chan<- Event
+	// frame is the next frame to play, which is past the last frame once the whole log has been played.
+	frame   int
+	paused  bool
+	speed   throttle
+	counter stepCounter
+}
+
+// run plays the log until it is told to stop.
+func (p *player) run(keyPresses <-chan rune, edits <-chan util.Cell) {
+	clock := p.log.Params.clock()
+	for {
+		if p.paused || p.frame == len(p.log.frames) {
+			// Nothing is played until a key is pressed.
+			select {
+			case key := <-keyPresses:
+				if p.handleKey(key) {
+					close(p.events)
+					return
+				}
+			case <-edits:
+			}
+			continue
+		}
+		select {
+		case key := <-keyPresses:
+			if p.handleKey(key) {
+				close(p.events)
+				return
+			}
+		case <-edits:
+		default:
+			// Wait in short sleeps when the rate is limited, so key presses are still handled.
+			if wait := p.speed.wait(); wait > 0 {
+				if wait > 10*time.Millisecond {
+					wait = 10 * time.Millisecond
+				}
+				clock.Sleep(wait)
+				break
+			}
+			p.speed.started()
+			p.play()
+		}
+	}
+}
+
+// handleKey acts on a key press, returning true if the replay should stop.
+func (p *player) handleKey(key rune) bool {
+	switch key {
+	case 'q', 'k':
+		return true
+	case 'p':
+		p.paused = !p.paused
+		state := Executing
+		if p.paused {
+			state = Paused
+		}
+		p.events <- StateChange{p.turn(), state}
+	case '.':
+		if p.paused && p.frame < len(p.log.frames) {
+			p.play()
+		}
+	case ',':
+		p.seek(p.log.frameAt(p.turn() - 1))
+	case 'g':
+		turn := p.counter.count
+		p.counter.count = 0
+		p.seek(p.log.frameAt(turn))
+	case 'n':
+		p.seek(p.log.frameAt(p.turn() + p.counter.take()))
+	default:
+		if !p.speed.handleKey(key) {
+			p.counter.handleKey(key)
+		}
+	}
+	return false
+}
+
+// turn returns the turns completed at the current position.
+func (p *player) turn() int {
+	if p.frame == len(p.log.frames) {
+		return p.log.Turns()
+	}
+	return p.log.frames[p.frame].turn
+}
+
+// play sends the Events of the next frame.
+func (p *player) play() {
+	for _, event := range p.log.events(p.frame) {
+		p.events <- event
+	}
+	p.frame++
+}
+
+// seek moves to the start of a frame, sending the Events that change the board shown into the board there.
+func (p *player) seek(frame int) {
+	if frame == p.frame {
+		return
+	}
+	from, to := p.log.board(p.frame), p.log.board(frame)
+	p.frame = frame
+	turn := p.turn()
+	p.events <- CellsFlipped{turn, from.difference(to)}
+	if p.log.Params.Unbounded && from.bounds != to.bounds {
+		p.events <- BoundsChanged{turn, to.bounds}
+	}
+	p.events <- TurnComplete{turn}
+}
@@ -0,0 +1,659 @@
+package gol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+var (
+	// ErrInvalidParams is returned when a Simulation is created with parameters it cannot run with.
+	ErrInvalidParams = errors.New("gol: invalid params")
+	// ErrBadImage is returned when an input image is not a pgm of the expected size.
+	ErrBadImage = errors.New("gol: bad pgm image")
+	// ErrOutOfBounds is returned when a cell outside of the board is edited.
+	ErrOutOfBounds = errors.New("gol: cell out of bounds")
+)
+
+// Simulation is a Game of Life board that is evolved by a Backend, which defaults to worker goroutines.
+// Unlike Run it is driven by method calls, so it can be embedded in other programs.
+// With Params.Unbounded set the board has no edges, and Board and Save only cover the region with alive cells in it.
+// Step, Run, Flip and Save may be called from any goroutine but run one at a time,
+// while Board, AliveCells and Turn can be called at any point, including during a Run.
+type Simulation struct {
+	params   Params
+	events   chan<- Event
+	imageDir string
+	outDir   string
+	initial  [][]byte
+	backend  Backend
+
+	// busy is held while the board is being changed or saved.
+	busy sync.Mutex
+	// mu guards world, universe, bounds, turn and period, which are only written while busy is held.
+	mu    sync.RWMutex
+	world [][]byte
+	turn  int
+	// history holds the hashes of the last boards, and period is the number of turns the board repeats after, once found.
+	history *history
+	period  int
+	// universe replaces world and the Backend when the board is unbounded, and bounds is the region of it that is alive.
+	universe *universe
+	bounds   image.Rectangle
+	// metrics records every turn if Params.Metrics is set. It is only used while busy is held.
+	metrics *metricsRecorder
+}
+
+// Option configures a Simulation created with NewSimulation.
+type Option func(*Simulation)
+
+// WithParams sets the size of the board, the number of worker threads and the number of turns Run processes.
+func WithParams(p Params) Option {
+	return func(s *Simulation) {
+		s.params = p
+	}
+}
+
+// WithEvents makes the Simulation send a CellsFlipped and TurnComplete Event for every turn,
+// or a CellFlipped Event per flipped cell with Params.PerCellEvents, and a CellFlipped Event for every edited cell. The channel must be drained, as sending blocks.
+func WithEvents(events chan<- Event) Option {
+	return func(s *Simulation) {
+		s.events = events
+	}
+}
+
+// WithBoard starts the Simulation from a copy of the given board instead of an image.
+// The board is indexed [y][x], with 255 for alive cells and 0 for dead ones,
+// and its size overrides the image size set in the params.
+func WithBoard(board [][]byte) Option {
+	return func(s *Simulation) {
+		s.initial = board
+	}
+}
+
+// WithBackend sets the Backend that evolves the board. Defaults to a Local one.
+// The Simulation stops the Backend when it is closed.
+func WithBackend(backend Backend) Option {
+	return func(s *Simulation) {
+		s.backend = backend
+	}
+}
+
+// WithImageDir sets the directory the starting image is read from. Defaults to "images".
+func WithImageDir(dir string) Option {
+	return func(s *Simulation) {
+		s.imageDir = dir
+	}
+}
+
+// WithOutputDir sets the directory Save writes images to. Defaults to "out".
+func WithOutputDir(dir string) Option {
+	return func(s *Simulation) {
+		s.outDir = dir
+	}
+}
+
+// NewSimulation creates a Simulation and loads its starting board, either from the board given to WithBoard,
+// from the soup in the params, or from the image in the image directory matching the params.
+func NewSimulation(opts ...Option) (*Simulation, error) {
+	s := &Simulation{
+		params:   Params{Threads: 1},
+		imageDir: "images",
+		outDir:   "out",
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.initial != nil {
+		s.params.ImageHeight = len(s.initial)
+		s.params.ImageWidth = 0
+		if len(s.initial) > 0 {
+			s.params.ImageWidth = len(s.initial[0])
+		}
+	}
+	if err := s.params.validate(); err != nil {
+		return nil, err
+	}
+
+	if s.initial != nil {
+		s.world = make([][]byte, s.params.ImageHeight)
+		for y, row := range s.initial {
+			if len(row) != s.params.ImageWidth {
+				return nil, fmt.Errorf("%w: row %v of the board has width %v, expected %v",
+					ErrInvalidParams, y, len(row), s.params.ImageWidth)
+			}
+			s.world[y] = append([]byte(nil), row...)
+		}
+		s.initial = nil
+	} else if s.params.Soup != nil {
+		world, err := s.params.Soup.Board(s.params.ImageWidth, s.params.ImageHeight)
+		if err != nil {
+			return nil, err
+		}
+		s.world = world
+	} else {
+		path := filepath.Join(s.imageDir, strconv.Itoa(s.params.ImageWidth)+"x"+strconv.Itoa(s.params.ImageHeight)+".pgm")
+		world, err := readPgmImage(path, s.params.ImageWidth, s.params.ImageHeight)
+		if err != nil {
+			return nil, err
+		}
+		s.world = world
+	}
+
+	if s.params.Unbounded {
+		if _, ok := s.backend.(*Local); s.backend != nil && !ok {
+			return nil, fmt.Errorf("%w: unbounded boards are evolved locally, not by a %T", ErrInvalidParams, s.backend)
+		}
+		s.backend = nil
+		s.universe = newUniverse(s.world, s.params.Threads)
+		s.bounds = s.universe.bounds()
+		s.world = nil
+		s.history = newHistory(s.universe.cells(), 0)
+		return s, s.startMetrics(0, 0, s.universe.cells())
+	}
+
+	if s.backend == nil {
+		s.backend = NewLocal()
+	}
+	if err := s.backend.Load(s.params, s.world); err != nil {
+		return nil, err
+	}
+	s.history = newHistory(calculateAliveCells(s.params, s.world), 0)
+	if err := s.startMetrics(s.params.ImageWidth, s.params.ImageHeight, calculateAliveCells(s.params, s.world)); err != nil {
+		s.backend.Stop()
+		return nil, err
+	}
+	return s, nil
+}
+
+// startMetrics creates the metrics file if the params ask for one, for a board of the given size and alive cells.
+func (s *Simulation) startMetrics(width, height int, aliveCells []util.Cell) error {
+	if s.params.Metrics == "" {
+		return nil
+	}
+	metrics, err := newMetricsRecorder(s.params.Metrics, width, height, aliveCells)
+	if err != nil {
+		return err
+	}
+	s.metrics = metrics
+	return nil
+}
+
+// validate checks that the params describe a board that can be simulated.
+func (p Params) validate() error {
+	switch {
+	case p.ImageWidth <= 0 || p.ImageHeight <= 0:
+		return fmt.Errorf("%w: the image must be at least 1x1, not %vx%v", ErrInvalidParams, p.ImageWidth, p.ImageHeight)
+	case p.Threads <= 0:
+		return fmt.Errorf("%w: at least 1 thread is needed, not %v", ErrInvalidParams, p.Threads)
+	case p.Turns < 0:
+		return fmt.Errorf("%w: the number of turns cannot be negative, not %v", ErrInvalidParams, p.Turns)
+	case p.ReportInterval < 0:
+		return fmt.Errorf("%w: the report interval cannot be negative, not %v", ErrInvalidParams, p.ReportInterval)
+	case p.Metrics != "" && !validMetricsPath(p.Metrics):
+		return fmt.Errorf("%w: metrics are written to a .csv or .jsonl file, not %q", ErrInvalidParams, p.Metrics)
+	}
+	return nil
+}
+
+// Params returns the params the Simulation was created with, including the size of its board.
+func (s *Simulation) Params() Params {
+	return s.params
+}
+
+// Turn returns the number of turns completed so far.
+func (s *Simulation) Turn() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.turn
+}
+
+// Board returns a copy of the current board, indexed [y][x].
+// For an unbounded board it is the region returned by Bounds, or a single dead cell if every cell is dead.
+func (s *Simulation) Board() [][]byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.universe != nil {
+		return s.universe.crop(s.liveRegion())
+	}
+	board := make([][]byte, len(s.world))
+	for y, row := range s.world {
+		board[y] = append([]byte(nil), row...)
+	}
+	return board
+}
+
+// AliveCells returns the cells that are currently alive.
+func (s *Simulation) AliveCells() []util.Cell {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.universe != nil {
+		return s.universe.cells()
+	}
+	return calculateAliveCells(s.params, s.world)
+}
+
+// Bounds returns the smallest rectangle containing every alive cell, which is empty if there are none.
+// For a bounded board it is always the whole board.
+func (s *Simulation) Bounds() image.Rectangle {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.universe != nil {
+		return s.bounds
+	}
+	return image.Rect(0, 0, s.params.ImageWidth, s.params.ImageHeight)
+}
+
+// liveRegion returns the part of an unbounded board that is output, which is never empty. Must be called with mu held.
+func (s *Simulation) liveRegion() image.Rectangle {
+	if s.bounds.Empty() {
+		return image.Rect(0, 0, 1, 1)
+	}
+	return s.bounds
+}
+
+// Period returns the number of turns after which the board repeats itself, 1 if it no longer changes,
+// or 0 if it has not been found to repeat. Repeats are looked for over the last 1024 turns, and only when
+// turns are processed one at a time, as Run does and Step does when the Simulation sends Events.
+func (s *Simulation) Period() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.period
+}
+
+// Step advances the board by n turns, regardless of the number of turns in the params.
+func (s *Simulation) Step(n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: cannot step %v turns", ErrInvalidParams, n)
+	}
+	s.busy.Lock()
+	defer s.busy.Unlock()
+	if s.events == nil && s.metrics == nil && n > 0 {
+		// Without Events to send or turns to record, the turns can all be processed by the Backend at once.
+		return s.advance(n)
+	}
+	for i := 0; i < n; i++ {
+		if err := s.advance(1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run advances the board until the number of turns in the params have been completed,
+// or until the board repeats itself if Params.UntilStable is set.
+// It stops early and returns the context's error if ctx is cancelled.
+func (s *Simulation) Run(ctx context.Context) error {
+	s.busy.Lock()
+	defer s.busy.Unlock()
+	for s.Turn() < s.params.Turns && !s.stopped() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.advance(1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopped returns true if the board has repeated itself and the params ask to stop once it does.
+func (s *Simulation) stopped() bool {
+	return s.params.UntilStable && s.Period() > 0
+}
+
+// Flip toggles the given cells between alive and dead.
+// Nothing is changed if any of the cells is outside of the board.
+func (s *Simulation) Flip(cells ...util.Cell) error {
+	if s.params.Unbounded {
+		return s.flipUnbounded(cells)
+	}
+	for _, cell := range cells {
+		if cell.X < 0 || cell.X >= s.params.ImageWidth || cell.Y < 0 || cell.Y >= s.params.ImageHeight {
+			return fmt.Errorf("%w: %v is not on a %vx%v board", ErrOutOfBounds, cell, s.params.ImageWidth, s.params.ImageHeight)
+		}
+	}
+	s.busy.Lock()
+	defer s.busy.Unlock()
+
+	s.mu.Lock()
+	for _, cell := range cells {
+		if s.world[cell.Y][cell.X] == alive {
+			s.world[cell.Y][cell.X] = dead
+		} else {
+			s.world[cell.Y][cell.X] = alive
+		}
+		if s.metrics != nil {
+			s.metrics.flip(cell, s.world[cell.Y][cell.X] == alive)
+		}
+	}
+	turn := s.turn
+	s.edited(cells)
+	s.mu.Unlock()
+
+	// Backends copy the board they are given, so it can be passed while busy is held.
+	if err := s.backend.Load(s.params, s.world); err != nil {
+		return err
+	}
+
+	for _, cell := range cells {
+		s.send(CellFlipped{
+			CompletedTurns: turn,
+			Cell:           cell,
+		})
+	}
+	return nil
+}
+
+// flipUnbounded is Flip for an unbounded board, on which every cell can be flipped.
+func (s *Simulation) flipUnbounded(cells []util.Cell) error {
+	s.busy.Lock()
+	defer s.busy.Unlock()
+
+	s.mu.Lock()
+	for _, cell := range cells {
+		s.universe.flip(cell)
+		if s.metrics != nil {
+			s.metrics.flip(cell, s.universe.alive(cell))
+		}
+	}
+	before := s.bounds
+	s.bounds = s.universe.bounds()
+	turn := s.turn
+	s.edited(cells)
+	s.mu.Unlock()
+
+	for _, cell := range cells {
+		s.send(CellFlipped{
+			CompletedTurns: turn,
+			Cell:           cell,
+		})
+	}
+	if s.bounds != before {
+		s.send(BoundsChanged{
+			CompletedTurns: turn,
+			Bounds:         s.bounds,
+		})
+	}
+	return nil
+}
+
+// edited updates the history with edited cells. An edited board has not repeated itself, whatever it was before.
+// Must be called with mu held.
+func (s *Simulation) edited(cells []util.Cell) {
+	s.history.flip(cells)
+	s.history.reset(s.turn)
+	s.period = 0
+}
+
+// Save writes the current board to a pgm image in the output directory,
+// named after the size of the board and the current turn. It returns the path of the image.
+// A board started from a soup has the soup in the name and in a comment in the image, so it can be generated again.
+// For an unbounded board the image is cropped to the region returned by Bounds.
+func (s *Simulation) Save() (string, error) {
+	s.busy.Lock()
+	defer s.busy.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	world := s.world
+	if s.universe != nil {
+		world = s.universe.crop(s.liveRegion())
+	}
+	path := filepath.Join(s.outDir, s.filename(len(world[0]), len(world))+".pgm")
+	return path, writePgmImage(path, world, s.comment())
+}
+
+// SaveRLE writes the current board to a run length encoded pattern file in the output directory,
+// named like the images Save writes. For an unbounded board the pattern is cropped to the region returned by Bounds,
+// and the position of its top left cell is recorded in the file. It returns the path of the file.
+func (s *Simulation) SaveRLE() (string, error) {
+	s.busy.Lock()
+	defer s.busy.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	world, region := s.world, image.Rect(0, 0, s.params.ImageWidth, s.params.ImageHeight)
+	if s.universe != nil {
+		region = s.liveRegion()
+		world = s.universe.crop(region)
+	}
+	path := filepath.Join(s.outDir, s.filename(region.Dx(), region.Dy())+".rle")
+	return path, writeRLE(path, world, region.Min, s.turn, s.comment())
+}
+
+// filename returns the name of a file the board is saved to without its extension: the size of the board
+// and the current turn, after the soup it was started from if there is one. Must be called with mu held.
+func (s *Simulation) filename(width, height int) string {
+	filename := strconv.Itoa(width) + "x" + strconv.Itoa(height) + "x" + strconv.Itoa(s.turn)
+	if s.params.Soup != nil {
+		filename = "soup-" + s.params.Soup.String() + "-" + filename
+	}
+	return filename
+}
+
+// comment returns the comment saved files record how the board was started with, or "" if it was not from a soup.
+func (s *Simulation) comment() string {
+	if s.params.Soup == nil {
+		return ""
+	}
+	return "soup " + s.params.Soup.String()
+}
+
+// Pause tells the Backend that the game has been paused.
+func (s *Simulation) Pause() error {
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Pause()
+}
+
+// Resume tells the Backend that the game has been resumed.
+func (s *Simulation) Resume() error {
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Resume()
+}
+
+// Close stops the Backend and closes the metrics file. The Simulation must not be used afterwards.
+func (s *Simulation) Close() error {
+	s.busy.Lock()
+	defer s.busy.Unlock()
+	if err := s.closeMetrics(); err != nil {
+		return err
+	}
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Stop()
+}
+
+// closeMetrics closes the metrics file, if there is one. Must be called with busy held.
+func (s *Simulation) closeMetrics() error {
+	if s.metrics == nil {
+		return nil
+	}
+	err := s.metrics.close()
+	s.metrics = nil
+	return err
+}
+
+// Shutdown closes the system the Backend runs on, if it can, then stops it.
+// The Simulation must not be used afterwards.
+func (s *Simulation) Shutdown() error {
+	s.busy.Lock()
+	defer s.busy.Unlock()
+	if err := s.closeMetrics(); err != nil {
+		return err
+	}
+	if s.backend == nil {
+		return nil
+	}
+	if b, ok := s.backend.(shutdowner); ok {
+		if err := b.Shutdown(); err != nil {
+			return err
+		}
+	}
+	return s.backend.Stop()
+}
+
+// advance processes the given number of turns, applies the flipped cells to the board and sends their Events.
+// Must be called with busy held.
+func (s *Simulation) advance(turns int) error {
+	if s.universe != nil {
+		return s.advanceUnbounded(turns)
+	}
+	turn := s.Turn()
+	start := s.params.clock().Now()
+	flipped, err := s.backend.Advance(turns)
+	if err != nil {
+		return err
+	}
+	duration := s.params.clock().Now().Sub(start)
+
+	births := 0
+	s.mu.Lock()
+	for _, cell := range flipped {
+		s.world[cell.Y][cell.X] ^= alive
+		if s.metrics != nil {
+			born := s.world[cell.Y][cell.X] == alive
+			s.metrics.flip(cell, born)
+			if born {
+				births++
+			}
+		}
+	}
+	s.turn += turns
+	found := s.repeated(flipped, turns)
+	s.mu.Unlock()
+
+	if s.metrics != nil {
+		err := s.metrics.record(Metrics{
+			Turn:     turn + turns,
+			Alive:    s.metrics.alive,
+			Births:   births,
+			Deaths:   len(flipped) - births,
+			Bounds:   s.metrics.bounds(),
+			Duration: duration,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.sendFlipped(turn+turns-1, flipped)
+	if found {
+		s.send(Stabilised{
+			CompletedTurns: turn + turns,
+			Period:         s.period,
+		})
+	}
+	s.send(TurnComplete{
+		CompletedTurns: turn + turns,
+	})
+	return nil
+}
+
+// advanceUnbounded is advance for an unbounded board, which also sends a BoundsChanged Event whenever its bounds change.
+func (s *Simulation) advanceUnbounded(turns int) error {
+	turn := s.Turn()
+	for i := 0; i < turns; i++ {
+		start := s.params.clock().Now()
+		s.mu.Lock()
+		flipped := s.universe.step()
+		before := s.bounds
+		s.bounds = s.universe.bounds()
+		s.turn++
+		found := s.repeated(flipped, 1)
+		s.mu.Unlock()
+
+		if s.metrics != nil {
+			duration := s.params.clock().Now().Sub(start)
+			births := 0
+			for _, cell := range flipped {
+				born := s.universe.alive(cell)
+				s.metrics.flip(cell, born)
+				if born {
+					births++
+				}
+			}
+			err := s.metrics.record(Metrics{
+				Turn:     turn + i + 1,
+				Alive:    s.metrics.alive,
+				Births:   births,
+				Deaths:   len(flipped) - births,
+				Bounds:   s.bounds,
+				Duration: duration,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if s.events == nil {
+			continue
+		}
+		s.sendFlipped(turn+i, flipped)
+		if s.bounds != before {
+			s.send(BoundsChanged{
+				CompletedTurns: turn + i + 1,
+				Bounds:         s.bounds,
+			})
+		}
+		if found {
+			s.send(Stabilised{
+				CompletedTurns: turn + i + 1,
+				Period:         s.period,
+			})
+		}
+		s.send(TurnComplete{
+			CompletedTurns: turn + i + 1,
+		})
+	}
+	return nil
+}
+
+// repeated updates the history with the cells flipped over the last turns, and returns true
+// if the board has just been found to repeat itself. Must be called with mu held.
+func (s *Simulation) repeated(flipped []util.Cell, turns int) bool {
+	s.history.flip(flipped)
+	if turns != 1 {
+		// The boards in between are not known, so the board can only be compared with later ones.
+		s.history.reset(s.turn)
+		return false
+	}
+	period := s.history.record(s.turn)
+	if period == 0 || s.period != 0 {
+		return false
+	}
+	s.period = period
+	return true
+}
+
+// send passes an Event on if the Simulation was created with an events channel.
+func (s *Simulation) send(event Event) {
+	if s.events != nil {
+		s.events <- event
+	}
+}
+
+// sendFlipped sends the cells flipped during a turn as a single CellsFlipped Event,
+// or as a CellFlipped Event for each of them if Params.PerCellEvents is set.
+func (s *Simulation) sendFlipped(turn int, cells []util.Cell) {
+	if s.events == nil {
+		return
+	}
+	if !s.params.PerCellEvents {
+		s.events <- CellsFlipped{CompletedTurns: turn, Cells: cells}
+		return
+	}
+	for _, cell := range cells {
+		s.events <- CellFlipped{CompletedTurns: turn, Cell: cell}
+	}
+}
@@ -0,0 +1,137 @@
+package gol
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// Symmetry is the symmetry a random soup is generated with.
+type Symmetry string
+
+const (
+	// C1 soups have no symmetry.
+	C1 Symmetry = "C1"
+	// C2 soups look the same after a half turn.
+	C2 Symmetry = "C2"
+	// C4 soups look the same after a quarter turn, and must be square.
+	C4 Symmetry = "C4"
+	// D2 soups are mirrored left to right.
+	D2 Symmetry = "D2"
+	// D4 soups are mirrored left to right and top to bottom.
+	D4 Symmetry = "D4"
+	// D8 soups look the same under every rotation and reflection of the square, and must be square.
+	D8 Symmetry = "D8"
+)
+
+// Soup describes a random board, which is the same every time it is generated from the same Soup.
+type Soup struct {
+	Seed int64
+	// Density is the chance of each cell being alive, from 0 to 1.
+	Density  float64
+	Symmetry Symmetry
+	// BoxWidth and BoxHeight limit the random cells to a box in the centre of the board, with every other cell dead.
+	// The whole board is filled if they are 0.
+	BoxWidth, BoxHeight int
+}
+
+// String describes the soup in a form that can be used in file names, such as "s42-d0.5-C1-b16x16".
+func (s Soup) String() string {
+	symmetry := s.Symmetry
+	if symmetry == "" {
+		symmetry = C1
+	}
+	name := "s" + strconv.FormatInt(s.Seed, 10) + "-d" + strconv.FormatFloat(s.Density, 'g', -1, 64) + "-" + string(symmetry)
+	if s.BoxWidth > 0 || s.BoxHeight > 0 {
+		name += "-b" + strconv.Itoa(s.BoxWidth) + "x" + strconv.Itoa(s.BoxHeight)
+	}
+	return name
+}
+
+// transforms returns the positions of a cell under every element of the symmetry group, including itself.
+func (s Symmetry) transforms(width, height int) ([]func(x, y int) (int, int), error) {
+	identity := func(x, y int) (int, int) { return x, y }
+	flipX := func(x, y int) (int, int) { return width - 1 - x, y }
+	flipY := func(x, y int) (int, int) { return x, height - 1 - y }
+	half := func(x, y int) (int, int) { return width - 1 - x, height - 1 - y }
+	quarter := func(x, y int) (int, int) { return width - 1 - y, x }
+	threeQuarters := func(x, y int) (int, int) { return y, height - 1 - x }
+	diagonal := func(x, y int) (int, int) { return y, x }
+	antidiagonal := func(x, y int) (int, int) { return width - 1 - y, height - 1 - x }
+
+	if (s == C4 || s == D8) && width != height {
+		return nil, fmt.Errorf("%w: %v soups must be square, not %vx%v", ErrInvalidParams, s, width, height)
+	}
+	switch s {
+	case C1, "":
+		return []func(x, y int) (int, int){identity}, nil
+	case C2:
+		return []func(x, y int) (int, int){identity, half}, nil
+	case C4:
+		return []func(x, y int) (int, int){identity, quarter, half, threeQuarters}, nil
+	case D2:
+		return []func(x, y int) (int, int){identity, flipX}, nil
+	case D4:
+		return []func(x, y int) (int, int){identity, flipX, flipY, half}, nil
+	case D8:
+		return []func(x, y int) (int, int){identity, flipX, flipY, half, quarter, threeQuarters, diagonal, antidiagonal}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown symmetry %q, expected C1, C2, C4, D2, D4 or D8", ErrInvalidParams, s)
+}
+
+// Board generates the soup on a board of the given size, indexed [y][x].
+// Cells are picked row by row, and every cell that the symmetry maps onto an earlier one is copied from it.
+func (s Soup) Board(width, height int) ([][]byte, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("%w: the soup must be at least 1x1, not %vx%v", ErrInvalidParams, width, height)
+	}
+	if s.BoxWidth > 0 || s.BoxHeight > 0 {
+		return s.boxed(width, height)
+	}
+	if s.Density < 0 || s.Density > 1 {
+		return nil, fmt.Errorf("%w: the density must be between 0 and 1, not %v", ErrInvalidParams, s.Density)
+	}
+	transforms, err := s.Symmetry.transforms(width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rand.New(rand.NewSource(s.Seed))
+	board := newBoard(width, height)
+	for y := range board {
+		for x := range board[y] {
+			first := true
+			for _, transform := range transforms {
+				tx, ty := transform(x, y)
+				if ty < y || ty == y && tx < x {
+					board[y][x] = board[ty][tx]
+					first = false
+					break
+				}
+			}
+			if first && r.Float64() < s.Density {
+				board[y][x] = alive
+			}
+		}
+	}
+	return board, nil
+}
+
+// boxed generates the soup inside its box, then places the box in the centre of a board of the given size.
+func (s Soup) boxed(width, height int) ([][]byte, error) {
+	if s.BoxWidth <= 0 || s.BoxHeight <= 0 || s.BoxWidth > width || s.BoxHeight > height {
+		return nil, fmt.Errorf("%w: a %vx%v box does not fit on a %vx%v board", ErrInvalidParams, s.BoxWidth, s.BoxHeight, width, height)
+	}
+	inner := s
+	inner.BoxWidth, inner.BoxHeight = 0, 0
+	box, err := inner.Board(s.BoxWidth, s.BoxHeight)
+	if err != nil {
+		return nil, err
+	}
+	board := newBoard(width, height)
+	left, top := (width-s.BoxWidth)/2, (height-s.BoxHeight)/2
+	for y, row := range box {
+		copy(board[top+y][left:], row)
+	}
+	return board, nil
+}
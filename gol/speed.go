@@ -0,0 +1,107 @@
+package gol
+
+import "time"
+
+const (
+	// maxRate is the fastest limit in turns per second. Going faster than it removes the limit.
+	maxRate = 1024
+	// minRate is the slowest limit in turns per second.
+	minRate = 0.25
+	// defaultSteps is the number of turns stepped by 'n' when no count has been typed.
+	defaultSteps = 10
+)
+
+// throttle limits how many turns are processed per second. A rate of 0 means there is no limit.
+type throttle struct {
+	clock Clock
+	rate  float64
+	next  time.Time
+}
+
+// faster doubles the rate, removing the limit once it goes over maxRate.
+func (t *throttle) faster() {
+	if t.rate == 0 {
+		return
+	}
+	t.rate *= 2
+	if t.rate > maxRate {
+		t.rate = 0
+	}
+}
+
+// slower halves the rate. Slowing down an unlimited run starts from maxRate.
+func (t *throttle) slower() {
+	if t.rate == 0 {
+		t.rate = maxRate
+	} else if t.rate/2 >= minRate {
+		t.rate /= 2
+	}
+}
+
+// unlimited removes the limit.
+func (t *throttle) unlimited() {
+	t.rate = 0
+}
+
+// handleKey changes the rate for '[' (slower), ']' (faster) and 'u' (unlimited).
+// It returns false for any other key.
+func (t *throttle) handleKey(key rune) bool {
+	switch key {
+	case '[':
+		t.slower()
+	case ']':
+		t.faster()
+	case 'u':
+		t.unlimited()
+	default:
+		return false
+	}
+	return true
+}
+
+// wait returns how long to wait before the next turn is allowed to start.
+func (t *throttle) wait() time.Duration {
+	if t.rate == 0 {
+		return 0
+	}
+	if d := t.next.Sub(t.clock.Now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// started records that a turn has started, scheduling when the next one may start.
+func (t *throttle) started() {
+	if t.rate == 0 {
+		return
+	}
+	now := t.clock.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	t.next = t.next.Add(time.Duration(float64(time.Second) / t.rate))
+}
+
+// stepCounter collects the digits typed before 'n' into the number of turns to step.
+type stepCounter struct {
+	count int
+}
+
+// handleKey records a digit and returns false for any other key.
+func (s *stepCounter) handleKey(key rune) bool {
+	if key < '0' || key > '9' {
+		return false
+	}
+	s.count = s.count*10 + int(key-'0')
+	return true
+}
+
+// take returns the typed number of turns, or defaultSteps if none was typed, and resets the count.
+func (s *stepCounter) take() int {
+	count := s.count
+	s.count = 0
+	if count == 0 {
+		return defaultSteps
+	}
+	return count
+}
@@ -0,0 +1,76 @@
+package gol
+
+import "uk.ac.bris.cs/gameoflife/util"
+
+// stableHistory is the number of past boards the board is compared with, and so the longest period that can be found.
+const stableHistory = 1024
+
+// history remembers the hashes of the last stableHistory boards, to find when the board starts repeating itself.
+// A board is hashed by XORing together a hash of every alive cell, so the hash can be updated from the flipped cells alone.
+// Two different boards could share a hash, but with 64 bit hashes this is not expected to happen in practice.
+type history struct {
+	hash uint64
+	// seen holds the last turn each remembered hash was seen at, and hashes the hash of each turn by turn % stableHistory.
+	seen   map[uint64]int
+	hashes []uint64
+	// first is the earliest turn that is remembered.
+	first int
+}
+
+// newHistory creates a history that starts with the board with the given alive cells, at the given turn.
+func newHistory(aliveCells []util.Cell, turn int) *history {
+	h := &history{
+		seen:   make(map[uint64]int),
+		hashes: make([]uint64, stableHistory),
+	}
+	h.flip(aliveCells)
+	h.reset(turn)
+	return h
+}
+
+// cellHash mixes the coordinates of a cell into 64 bits, using the finaliser of SplitMix64.
+func cellHash(cell util.Cell) uint64 {
+	z := uint64(uint32(cell.X))<<32 | uint64(uint32(cell.Y))
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// flip updates the hash of the board with cells that flipped.
+func (h *history) flip(cells []util.Cell) {
+	for _, cell := range cells {
+		h.hash ^= cellHash(cell)
+	}
+}
+
+// reset forgets every earlier board, leaving the current one as the board at the given turn.
+// It is called when the board changes other than by a single turn, so it cannot be compared with the boards before.
+func (h *history) reset(turn int) {
+	h.seen = make(map[uint64]int)
+	h.first = turn
+	h.add(turn)
+}
+
+// record remembers the current board as the board at the given turn, which must follow the last one remembered.
+// It returns the number of turns since the board was last the same, or 0 if it is not one of the remembered boards.
+func (h *history) record(turn int) int {
+	period := 0
+	if last, ok := h.seen[h.hash]; ok && last >= h.first {
+		period = turn - last
+	}
+	if turn-h.first >= stableHistory {
+		// The oldest board is forgotten, unless its hash was seen again since.
+		oldest := turn - stableHistory
+		if last := h.seen[h.hashes[oldest%stableHistory]]; last == oldest {
+			delete(h.seen, h.hashes[oldest%stableHistory])
+		}
+		h.first = oldest + 1
+	}
+	h.add(turn)
+	return period
+}
+
+func (h *history) add(turn int) {
+	h.seen[h.hash] = turn
+	h.hashes[turn%stableHistory] = h.hash
+}
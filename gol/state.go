@@ -0,0 +1,58 @@
+package gol
+
+import (
+	"fmt"
+	"sync"
+)
+
+// transitions lists the states of execution that can be reached from each state.
+//
+//	Loading   -> Executing, Quitting
+//	Executing -> Paused, Saving, Finished, Quitting
+//	Paused    -> Executing, Saving, Quitting
+//	Saving    -> Executing, Paused, Quitting
+//	Finished  -> Saving, Quitting
+//	Quitting  -> nothing, the events channel is closed afterwards
+var transitions = map[State][]State{
+	Loading:   {Executing, Quitting},
+	Executing: {Paused, Saving, Finished, Quitting},
+	Paused:    {Executing, Saving, Quitting},
+	Saving:    {Executing, Paused, Quitting},
+	Finished:  {Saving, Quitting},
+	Quitting:  {},
+}
+
+// stateMachine keeps track of the state of execution and sends a StateChange Event for every transition.
+type stateMachine struct {
+	mu     sync.Mutex
+	state  State
+	events chan<- Event
+}
+
+// newStateMachine starts in the Loading state and announces it with a StateChange Event.
+func newStateMachine(events chan<- Event) *stateMachine {
+	events <- StateChange{0, Loading}
+	return &stateMachine{state: Loading, events: events}
+}
+
+// current returns the state of execution.
+func (m *stateMachine) current() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// to moves to a new state at the given turn.
+// It panics if the new state can't be reached from the current one, as that is a bug in the caller.
+func (m *stateMachine) to(turn int, state State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, next := range transitions[m.state] {
+		if next == state {
+			m.state = state
+			m.events <- StateChange{turn, state}
+			return
+		}
+	}
+	panic(fmt.Sprintf("invalid state change from %v to %v", m.state, state))
+}
@@ -0,0 +1,65 @@
+package gol
+
+// tileSize is the width and height of the tiles the board is split into to track which parts of it are changing.
+const tileSize = 16
+
+// tiling splits a board into tiles of tileSize by tileSize cells, numbered row by row.
+// The last row and column of tiles are smaller when the size of the board is not a multiple of tileSize.
+type tiling struct {
+	width, height  int
+	tilesX, tilesY int
+}
+
+func newTiling(width, height int) tiling {
+	return tiling{
+		width:  width,
+		height: height,
+		tilesX: (width + tileSize - 1) / tileSize,
+		tilesY: (height + tileSize - 1) / tileSize,
+	}
+}
+
+// count returns the number of tiles.
+func (t tiling) count() int {
+	return t.tilesX * t.tilesY
+}
+
+// bounds returns the cells covered by tile i, from startX, startY up to but not including endX, endY.
+func (t tiling) bounds(i int) (startX, startY, endX, endY int) {
+	startX, startY = i%t.tilesX*tileSize, i/t.tilesX*tileSize
+	endX, endY = startX+tileSize, startY+tileSize
+	if endX > t.width {
+		endX = t.width
+	}
+	if endY > t.height {
+		endY = t.height
+	}
+	return startX, startY, endX, endY
+}
+
+// spread marks the tiles that have to be evolved next turn as active: every changed tile and the tiles around it,
+// wrapping around the edges of the board. A tile that is not active cannot change, as none of its cells
+// or their neighbours changed last turn.
+func (t tiling) spread(changed, active []bool) {
+	for i := range active {
+		active[i] = false
+	}
+	for i, c := range changed {
+		if !c {
+			continue
+		}
+		x, y := i%t.tilesX, i/t.tilesX
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				active[mod(y+dy, t.tilesY)*t.tilesX+mod(x+dx, t.tilesX)] = true
+			}
+		}
+	}
+}
+
+// activateAll marks every tile as active, for the first turn after a board is loaded.
+func activateAll(active []bool) {
+	for i := range active {
+		active[i] = true
+	}
+}
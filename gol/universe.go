@@ -0,0 +1,250 @@
+package gol
+
+import (
+	"image"
+	"sort"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// chunkSize is the width and height of the chunks an unbounded universe is stored in.
+const chunkSize = tileSize
+
+// chunk holds the cells of a chunkSize by chunkSize square of an unbounded universe, indexed [y][x].
+type chunk [chunkSize][chunkSize]byte
+
+// chunkPos is the position of a chunk, counted in chunks from the one whose top left cell is 0, 0.
+type chunkPos struct {
+	X, Y int
+}
+
+// universe is an unbounded board that only stores the chunks with alive cells in them,
+// so it grows as cells appear anywhere instead of wrapping around like the bounded board.
+// Like the tiles of the bounded backends, only the chunks where a cell flipped last turn,
+// and the chunks around them, are evolved.
+type universe struct {
+	chunks  map[chunkPos]*chunk
+	changed map[chunkPos]bool
+	threads int
+}
+
+// newUniverse creates a universe with the alive cells of world, its top left cell being 0, 0.
+func newUniverse(world [][]byte, threads int) *universe {
+	u := &universe{
+		chunks:  make(map[chunkPos]*chunk),
+		changed: make(map[chunkPos]bool),
+		threads: threads,
+	}
+	for y, row := range world {
+		for x, cell := range row {
+			if cell == alive {
+				u.flip(util.Cell{X: x, Y: y})
+			}
+		}
+	}
+	return u
+}
+
+// floorDiv divides rounding towards negative infinity, so that negative cells fall in negative chunks.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+// locate returns the chunk a cell is in, and its position inside the chunk.
+func locate(x, y int) (chunkPos, int, int) {
+	pos := chunkPos{floorDiv(x, chunkSize), floorDiv(y, chunkSize)}
+	return pos, x - pos.X*chunkSize, y - pos.Y*chunkSize
+}
+
+// flip toggles a cell between alive and dead.
+func (u *universe) flip(cell util.Cell) {
+	pos, x, y := locate(cell.X, cell.Y)
+	c := u.chunks[pos]
+	if c == nil {
+		c = new(chunk)
+		u.chunks[pos] = c
+	}
+	c[y][x] ^= alive
+	u.changed[pos] = true
+	if c.empty() {
+		delete(u.chunks, pos)
+	}
+}
+
+// alive returns true if a cell is alive.
+func (u *universe) alive(cell util.Cell) bool {
+	pos, x, y := locate(cell.X, cell.Y)
+	c := u.chunks[pos]
+	return c != nil && c[y][x] == alive
+}
+
+// empty returns true if every cell of the chunk is dead.
+func (c *chunk) empty() bool {
+	for y := range c {
+		for x := range c[y] {
+			if c[y][x] == alive {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// withBorder returns the cells of the chunk at pos with a border of one cell around it,
+// taken from the chunks next to it, in the form calculateNextTile evolves.
+func (u *universe) withBorder(pos chunkPos) [][]byte {
+	world := make([][]byte, chunkSize+2)
+	for y := range world {
+		world[y] = make([]byte, chunkSize+2)
+	}
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			c := u.chunks[chunkPos{pos.X + dx, pos.Y + dy}]
+			if c == nil {
+				continue
+			}
+			// Only the cells of the neighbouring chunks that touch the chunk at pos are copied.
+			for y := 0; y < chunkSize+2; y++ {
+				cy := y - 1 - dy*chunkSize
+				if cy < 0 || cy >= chunkSize {
+					continue
+				}
+				for x := 0; x < chunkSize+2; x++ {
+					cx := x - 1 - dx*chunkSize
+					if cx >= 0 && cx < chunkSize {
+						world[y][x] = c[cy][cx]
+					}
+				}
+			}
+		}
+	}
+	return world
+}
+
+// step evolves the universe by one turn and returns the cells that flipped.
+func (u *universe) step() []util.Cell {
+	active := make(map[chunkPos]bool)
+	for pos := range u.changed {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				active[chunkPos{pos.X + dx, pos.Y + dy}] = true
+			}
+		}
+	}
+	positions := make([]chunkPos, 0, len(active))
+	for pos := range active {
+		positions = append(positions, pos)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Y < positions[j].Y || positions[i].Y == positions[j].Y && positions[i].X < positions[j].X
+	})
+
+	// Every active chunk is evolved before any is written back, split between the threads.
+	next := make([][][]byte, len(positions))
+	done := make(chan bool, u.threads)
+	for i := 0; i < u.threads; i++ {
+		first, last := split(len(positions), u.threads, i)
+		go func() {
+			for j := first; j < last; j++ {
+				next[j] = calculateNextTile(u.withBorder(positions[j]))
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < u.threads; i++ {
+		<-done
+	}
+
+	var flipped []util.Cell
+	u.changed = make(map[chunkPos]bool)
+	for j, pos := range positions {
+		c := u.chunks[pos]
+		if c == nil {
+			c = new(chunk)
+		}
+		for y := range c {
+			for x := range c[y] {
+				if c[y][x] != next[j][y][x] {
+					c[y][x] = next[j][y][x]
+					u.changed[pos] = true
+					flipped = append(flipped, util.Cell{X: pos.X*chunkSize + x, Y: pos.Y*chunkSize + y})
+				}
+			}
+		}
+		if !u.changed[pos] {
+			continue
+		}
+		if c.empty() {
+			delete(u.chunks, pos)
+		} else {
+			u.chunks[pos] = c
+		}
+	}
+	return flipped
+}
+
+// sortedChunks returns the positions of the stored chunks row by row.
+func (u *universe) sortedChunks() []chunkPos {
+	positions := make([]chunkPos, 0, len(u.chunks))
+	for pos := range u.chunks {
+		positions = append(positions, pos)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Y < positions[j].Y || positions[i].Y == positions[j].Y && positions[i].X < positions[j].X
+	})
+	return positions
+}
+
+// cells returns every alive cell.
+func (u *universe) cells() []util.Cell {
+	aliveCells := []util.Cell{}
+	for _, pos := range u.sortedChunks() {
+		c := u.chunks[pos]
+		for y := range c {
+			for x := range c[y] {
+				if c[y][x] == alive {
+					aliveCells = append(aliveCells, util.Cell{X: pos.X*chunkSize + x, Y: pos.Y*chunkSize + y})
+				}
+			}
+		}
+	}
+	return aliveCells
+}
+
+// bounds returns the smallest rectangle containing every alive cell, which is empty if there are none.
+func (u *universe) bounds() image.Rectangle {
+	var r image.Rectangle
+	for pos, c := range u.chunks {
+		// Chunks inside the rectangle found so far cannot widen it, so they are not looked at cell by cell.
+		outer := image.Rect(pos.X*chunkSize, pos.Y*chunkSize, (pos.X+1)*chunkSize, (pos.Y+1)*chunkSize)
+		if !r.Empty() && outer.In(r) {
+			continue
+		}
+		for y := range c {
+			for x := range c[y] {
+				if c[y][x] == alive {
+					r = r.Union(image.Rect(outer.Min.X+x, outer.Min.Y+y, outer.Min.X+x+1, outer.Min.Y+y+1))
+				}
+			}
+		}
+	}
+	return r
+}
+
+// crop returns the cells inside r as a board indexed [y][x] from its top left corner.
+func (u *universe) crop(r image.Rectangle) [][]byte {
+	board := make([][]byte, r.Dy())
+	for y := range board {
+		board[y] = make([]byte, r.Dx())
+		for x := range board[y] {
+			pos, cx, cy := locate(r.Min.X+x, r.Min.Y+y)
+			if c := u.chunks[pos]; c != nil {
+				board[y][x] = c[cy][cx]
+			}
+		}
+	}
+	return board
+}
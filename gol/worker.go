@@ -0,0 +1,177 @@
+package gol
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/rpc"
+	"os"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+//Worker calculates the next state of the strips of the world it is sent, using a number of threads
+type Worker struct {
+	threads int
+	//exit is called once the worker has been closed
+	exit func()
+}
+
+//creates a worker that uses the given number of threads, or a single thread if it is given fewer than one
+func NewWorker(threads int) *Worker {
+	if threads < 1 {
+		threads = 1
+	}
+	return &Worker{
+		threads: threads,
+		exit: func() {
+			//closes the worker after 4 seconds
+			time.Sleep(4 * time.Second)
+			os.Exit(0)
+		},
+	}
+}
+
+//SetWorkerExit replaces what happens once the worker has been closed, which exits the program by default
+//it is a function rather than a method so that it is not published over rpc
+func SetWorkerExit(w *Worker, exit func()) {
+	w.exit = exit
+}
+
+//ServeWorker publishes the worker's methods on every connection accepted by l, until l is closed
+func ServeWorker(w *Worker, l net.Listener) {
+	server := rpc.NewServer()
+	server.Register(w)
+	server.Accept(l)
+}
+
+//RegisterWith asks the engine behind client to send work to the worker listening on address
+func RegisterWith(client *rpc.Client, address string) error {
+	status := new(StatusReport)
+	return client.Call(Register, RegisterWorker{address}, status)
+}
+
+//function that is called when closing the workers
+func (w *Worker) CloseWorker(req bool, res *bool) (err error) {
+	fmt.Println("Closing worker...")
+	go w.exit()
+	return nil
+}
+
+//returns the alive cells in a slice of util.Cells
+func (w *Worker) CalculateAliveCells(req VisualiseCellsRequest, res *AliveReport) (err error) {
+	for y := range req.World {
+		for x := range req.World[y] {
+			if req.World[y][x] == alive {
+				res.Cells = append(res.Cells, util.Cell{X: x, Y: y})
+			}
+		}
+	}
+	return nil
+}
+
+//returns the next state of the cells inside the border of a tile
+func calculateNextTile(world [][]byte) [][]byte {
+	newWorld := make([][]byte, len(world)-2)
+	for i := range newWorld {
+		newWorld[i] = make([]byte, len(world[0])-2)
+		for j := range newWorld[i] {
+			//the tile is shifted by one cell in the world, as its border comes first
+			y, x := i+1, j+1
+			aliveNeighbours := int(world[y-1][x-1]) + int(world[y-1][x]) + int(world[y-1][x+1]) + int(world[y][x+1]) + int(world[y+1][x+1]) + int(world[y+1][x]) + int(world[y+1][x-1]) + int(world[y][x-1])
+			aliveNeighbours /= alive
+			if aliveNeighbours == 3 || aliveNeighbours == 2 && world[y][x] == alive {
+				newWorld[i][j] = alive
+			}
+		}
+	}
+	return newWorld
+}
+
+//function that is called through rpc
+//takes the tiles of the board it recieves and splits them depending of the number of threads it has available, returning their next state
+func (w *Worker) CalculateTiles(req TilesRequest, res *TilesReport) (err error) {
+	for i, tile := range req.Tiles {
+		if len(tile) < 3 {
+			return fmt.Errorf("tile %v has %v rows, at least 3 are needed", i, len(tile))
+		}
+		for _, row := range tile {
+			if len(row) < 3 || len(row) != len(tile[0]) {
+				return fmt.Errorf("tile %v has rows of %v and %v columns, at least 3 are needed", i, len(tile[0]), len(row))
+			}
+		}
+	}
+	res.Tiles = make([][][]byte, len(req.Tiles))
+	done := make(chan bool, w.threads)
+	//start all the goroutines depending on the number of threads, each with its own tiles
+	for i := 0; i < w.threads; i++ {
+		first, last := split(len(req.Tiles), w.threads, i)
+		go func() {
+			for tile := first; tile < last; tile++ {
+				res.Tiles[tile] = calculateNextTile(req.Tiles[tile])
+			}
+			done <- true
+		}()
+	}
+	//waits for every thread to finish
+	for i := 0; i < w.threads; i++ {
+		<-done
+	}
+	res.Done = true
+	return nil
+}
+
+//starts the listener and registers on the engine
+func Work(port string, engineAddr string, thr int) {
+	//the listener has to be ready before registering, as the engine connects back straight away
+	workerListener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer workerListener.Close()
+
+	client, err := rpc.Dial("tcp", engineAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn, err := net.Dial("udp", "8.8.8.8:80")
+	if err != nil {
+		log.Fatal(err)
+	}
+	localAddr := conn.LocalAddr().(*net.UDPAddr)
+	conn.Close()
+
+	name, err := os.Hostname()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	addrs, err := net.LookupHost(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var add string
+	for _, a := range addrs {
+		add = a
+	}
+
+	fmt.Println("Worker local address:" + add + ":" + port)
+	fmt.Println("Worker internet address:" + localAddr.IP.To4().String() + ":" + port)
+	worker := NewWorker(thr)
+	fmt.Println("Threads:", worker.threads)
+
+	done := make(chan bool)
+	go func() {
+		ServeWorker(worker, workerListener)
+		done <- true
+	}()
+	if err := RegisterWith(client, add+":"+port); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Worker registered.")
+
+	<-done
+}
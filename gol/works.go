@@ -0,0 +1,74 @@
+package gol
+
+import (
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+var Register = "Engine.Register"
+var Load = "Engine.Load"
+var Advance = "Engine.Advance"
+var ReturnBoardState = "Engine.ReturnBoardState"
+var Pause = "Engine.Pause"
+var Unpause = "Engine.Unpause"
+var RenewPause = "Engine.RenewPause"
+var CloseSystem = "Engine.CloseSystem"
+var CalculateTiles = "Worker.CalculateTiles"
+var CloseWorker = "Worker.CloseWorker"
+var CalculateAliveCells = "Worker.CalculateAliveCells"
+
+type VisualiseCellsRequest struct {
+	ImageHeight int
+	ImWidth     int
+	X           int
+	Dx          int
+	World       [][]byte
+}
+
+//Client identifies the controller making a request, so that the one that paused the engine can still use it
+type LoadRequest struct {
+	Client      string
+	ImageHeight int
+	ImageWidth  int
+	World       [][]byte
+}
+
+type AdvanceRequest struct {
+	Client string
+	Turns  int
+}
+
+type AdvanceReport struct {
+	Turns   int
+	Flipped []util.Cell
+}
+
+type TilesReport struct {
+	Tiles [][][]byte
+	Done  bool
+}
+
+type RegisterWorker struct {
+	WorkerAddres string
+}
+
+type StatusReport struct {
+	Turns int
+	World [][]byte
+}
+
+type AliveReport struct {
+	Cells []util.Cell
+}
+
+type PauseReport struct {
+	Turns int
+	//Lease is how long the pause lasts unless it is renewed
+	Lease time.Duration
+}
+
+//every tile has a border of one cell from the tiles around it, which is left out of its next state in the report
+type TilesRequest struct {
+	Tiles [][][]byte
+}
@@ -8,12 +8,18 @@ import (
 	"uk.ac.bris.cs/gameoflife/util"
 )
 
-// TestGol tests 16x16, 64x64 and 512x512 images on 0, 1 and 100 turns using 1-16 worker threads.
+// TestGol tests 16x16, 64x64, 512x512 and non-square images on 0, 1 and 100 turns using 1-16 worker threads.
+// The 3x40 and 40x3 images have fewer columns or rows than there are threads.
 func TestGol(t *testing.T) {
 	tests := []gol.Params{
 		{ImageWidth: 16, ImageHeight: 16},
 		{ImageWidth: 64, ImageHeight: 64},
 		{ImageWidth: 512, ImageHeight: 512},
+		{ImageWidth: 64, ImageHeight: 16},
+		{ImageWidth: 16, ImageHeight: 64},
+		{ImageWidth: 100, ImageHeight: 37},
+		{ImageWidth: 3, ImageHeight: 40},
+		{ImageWidth: 40, ImageHeight: 3},
 	}
 	for _, p := range tests {
 		for _, turns := range []int{0, 1, 100} {
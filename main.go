@@ -0,0 +1,293 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"uk.ac.bris.cs/gameoflife/analysis"
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/sdl"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// main is the function called when starting Game of Life with 'go run .'
+func main() {
+	runtime.LockOSThread()
+	var params gol.Params
+	var backend string
+	var port string
+	var typ string
+	var engineAddress string
+	var diskPath string
+	var search analysis.SearchParams
+	var symmetry string
+	var soup bool
+	var box string
+	var record string
+	var replay string
+	var rate float64
+
+	flag.IntVar(
+		&params.Threads,
+		"t",
+		8,
+		"Specify the number of worker threads to use, or for a Worker, the number of threads it uses. Defaults to 8.")
+
+	flag.IntVar(
+		&params.ImageWidth,
+		"w",
+		512,
+		"Specify the width of the image. Defaults to 512.")
+
+	flag.IntVar(
+		&params.ImageHeight,
+		"h",
+		512,
+		"Specify the height of the image. Defaults to 512.")
+
+	flag.IntVar(
+		&params.Turns,
+		"turns",
+		10000,
+		"Specify the number of turns to process. Defaults to 10000.")
+
+	flag.BoolVar(
+		&params.Unbounded,
+		"unbounded",
+		false,
+		"Evolve the image on an infinite board instead of wrapping it around its edges, always with the local backend.")
+
+	flag.BoolVar(
+		&params.UntilStable,
+		"until-stable",
+		false,
+		"Stop once the board repeats itself, with a period of up to 1024 turns, instead of processing every turn.")
+
+	flag.BoolVar(
+		&params.PerCellEvents,
+		"per-cell-events",
+		false,
+		"Send a CellFlipped event for every flipped cell instead of a single CellsFlipped event per turn.")
+
+	flag.StringVar(&params.Metrics,
+		"metrics",
+		"",
+		"Specify a .csv or .jsonl file to record the alive cells, births, deaths, bounding box and duration of every turn to.")
+
+	flag.StringVar(&record,
+		"record",
+		"",
+		"Specify a file to record the events of the run and the keys pressed during it to, to be played back with -replay.")
+	flag.StringVar(&replay,
+		"replay",
+		"",
+		"Specify an event log recorded with -record to play back instead of running the Game of Life.")
+	flag.Float64Var(&rate,
+		"rate",
+		30,
+		"Specify the turns per second a -replay starts playing at, or 0 for no limit. Defaults to 30.")
+
+	flag.StringVar(&backend,
+		"backend",
+		"local",
+		"Specify how the board is evolved: 'local' with goroutines or 'cluster' with the engine and its workers. Defaults to local.")
+	flag.StringVar(&typ,
+		"Type",
+		"controller",
+		"Specify what type of instance you are starting: controller, Engine, Worker, Disk, Census or Search. Defaults to controller.")
+	flag.StringVar(&port,
+		"Port",
+		"8030",
+		"Specify the port an Engine or Worker listens on.")
+	flag.StringVar(&engineAddress,
+		"EngineAddress",
+		"127.0.0.1:8040",
+		"Specify the address of the engine, used by the cluster backend and by Workers.")
+	flag.StringVar(&diskPath,
+		"disk",
+		"board.golt",
+		"Specify the file of the board a Disk instance evolves without holding it in memory, imported from the image if it does not exist.")
+	flag.IntVar(&search.Soups,
+		"soups",
+		1000,
+		"Specify the number of random soups a Search instance evolves, each the size of the image. Defaults to 1000.")
+	flag.BoolVar(&soup,
+		"soup",
+		false,
+		"Start from a random soup generated from -seed, -density, -symmetry and -box instead of the image.")
+	flag.StringVar(&box,
+		"box",
+		"",
+		"Specify the size of the box in the centre of the board a -soup is generated in, such as 16x16. Defaults to the whole board.")
+	flag.Int64Var(&search.Seed,
+		"seed",
+		1,
+		"Specify the seed the random soups are generated from. Defaults to 1.")
+	flag.Float64Var(&search.Density,
+		"density",
+		0.5,
+		"Specify the chance of each cell of a random soup being alive. Defaults to 0.5.")
+	flag.StringVar(&symmetry,
+		"symmetry",
+		"C1",
+		"Specify the symmetry of random soups: C1, C2, C4, D2, D4 or D8. Defaults to C1.")
+
+	flag.Parse()
+
+	if typ == "Engine" {
+		fmt.Println("Engine")
+		gol.Eng(port)
+		return
+	} else if typ == "Worker" {
+		fmt.Println("Worker")
+		gol.Work(port, engineAddress, params.Threads)
+		return
+	} else if typ == "Disk" {
+		fmt.Println("Disk")
+		if err := gol.RunDisk(params, diskPath, "images", "out"); err != nil {
+			fmt.Println(err)
+		}
+		return
+	} else if typ == "Census" {
+		fmt.Println("Census")
+		census, err := analysis.Survey(params, "images", "out")
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for _, count := range census.Counts {
+			name := count.Name
+			if name == "" {
+				name = count.Code
+			}
+			fmt.Printf("%-8v%v\n", count.Count, name)
+		}
+		return
+	} else if typ == "Search" {
+		fmt.Println("Search")
+		search.Width, search.Height = params.ImageWidth, params.ImageHeight
+		search.Symmetry = gol.Symmetry(symmetry)
+		search.MaxTurns = params.Turns
+		search.Threads = params.Threads
+		if err := runSearch(search, "out"); err != nil {
+			fmt.Println(err)
+		}
+		return
+	} else if typ != "controller" {
+		fmt.Println("Invalid inputs...")
+		return
+	}
+	if replay != "" {
+		if err := runReplay(replay, rate); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
+	if soup {
+		params.Soup = &gol.Soup{Seed: search.Seed, Density: search.Density, Symmetry: gol.Symmetry(symmetry)}
+		if box != "" {
+			if _, err := fmt.Sscanf(box, "%dx%d", &params.Soup.BoxWidth, &params.Soup.BoxHeight); err != nil {
+				fmt.Println("Invalid box size:", box)
+				return
+			}
+		}
+	}
+	if params.Unbounded && backend != "local" {
+		fmt.Println("Unbounded boards can only be evolved with the local backend.")
+		return
+	}
+	if err := gol.UseBackend(backend, engineAddress); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Threads:", params.Threads)
+	fmt.Println("Width:", params.ImageWidth)
+	fmt.Println("Height:", params.ImageHeight)
+	fmt.Println("Backend:", backend)
+	fmt.Println("Unbounded:", params.Unbounded)
+	if params.Soup != nil {
+		fmt.Println("Soup:", params.Soup)
+	}
+	if params.Metrics != "" {
+		fmt.Println("Metrics:", params.Metrics)
+	}
+
+	keyPresses := make(chan rune, 10)
+	events := make(chan gol.Event, 1000)
+	edits := make(chan util.Cell, 10)
+
+	// A recording sits between the run and SDL, so it sees the events and key presses that pass between them.
+	var shownEvents <-chan gol.Event = events
+	var pressedKeys chan<- rune = keyPresses
+	var recorder *gol.EventRecorder
+	if record != "" {
+		var err error
+		recorder, err = gol.CreateEventLog(record, params)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("Recording:", record)
+		shownEvents = recorder.Events(events)
+		pressedKeys = recorder.KeyPresses(keyPresses)
+	}
+
+	gol.RunWithEdits(params, events, keyPresses, edits)
+	sdl.Start(params, shownEvents, pressedKeys, edits)
+	if recorder != nil && recorder.Err() != nil {
+		fmt.Println(recorder.Err())
+	}
+}
+
+// runReplay plays back the event log at path in SDL, starting at rate turns per second.
+func runReplay(path string, rate float64) error {
+	log, err := gol.OpenEventLog(path)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Replaying:", path)
+	fmt.Println("Width:", log.Params.ImageWidth)
+	fmt.Println("Height:", log.Params.ImageHeight)
+	fmt.Println("Turns:", log.Turns())
+
+	keyPresses := make(chan rune, 10)
+	events := make(chan gol.Event, 1000)
+	edits := make(chan util.Cell, 10)
+
+	gol.Replay(log, rate, events, keyPresses, edits)
+	sdl.Start(log.Params, events, keyPresses, edits)
+	return nil
+}
+
+// runSearch runs a soup search and writes its catalogue to outDir, printing the rare objects it found.
+func runSearch(search analysis.SearchParams, outDir string) error {
+	catalogue, err := analysis.Search(search)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return err
+	}
+	path := filepath.Join(outDir, fmt.Sprintf("search-%v-%vx%v-%v-%v.json", search.Seed, search.Width, search.Height, search.Symmetry, search.Soups))
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := catalogue.WriteJSON(file); err != nil {
+		return err
+	}
+	fmt.Println("File", filepath.Base(path), "output done!")
+
+	fmt.Println("Unstable soups:", len(catalogue.Unstable))
+	for _, entry := range catalogue.Objects {
+		if entry.Rare {
+			fmt.Printf("Rare %-24v %v %v in %v soups, first seed %v\n", entry.Code, entry.Name, entry.Kind, entry.Soups, entry.Seeds[0])
+		}
+	}
+	return file.Close()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/clustertest"
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+var (
+	backend       = flag.String("backend", "local", "Backend to run the tests with: 'local' or 'cluster'.")
+	engineAddress = flag.String("engine", "", "Address of the engine used by the cluster backend. If empty, one is started inside the tests.")
+	workers       = flag.Int("workers", 2, "Number of workers of the engine started inside the tests.")
+	pipe          = flag.Bool("pipe", false, "Connect the engine started inside the tests through net.Pipe instead of tcp.")
+)
+
+// cluster is the engine started inside the tests, if any.
+var cluster *clustertest.Cluster
+
+// TestMain selects the backend every test runs with. 'go test -args -backend=cluster' starts an engine
+// and workers inside the tests, while adding '-engine=127.0.0.1:8040' uses one that is already running.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *backend == "cluster" && *engineAddress == "" {
+		transport := clustertest.TCP
+		if *pipe {
+			transport = clustertest.Pipe
+		}
+		var err error
+		cluster, err = clustertest.Start(transport, *workers, 2)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		gol.UseBackendFunc(cluster.Backend)
+	} else if err := gol.UseBackend(*backend, *engineAddress); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	code := m.Run()
+	if cluster != nil {
+		cluster.Close()
+	}
+	os.Exit(code)
+}
+
+// testBackend creates a new backend of the type selected for the tests.
+func testBackend(t *testing.T) gol.Backend {
+	var b gol.Backend
+	var err error
+	if cluster != nil {
+		b, err = cluster.Backend()
+	} else {
+		b, err = gol.NewBackend(*backend, *engineAddress)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
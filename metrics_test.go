@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// readMetrics reads every line of a CSV or JSON Lines metrics file into a map from its columns to their values.
+func readMetrics(t *testing.T, path string) []map[string]int64 {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var lines []map[string]int64
+	if filepath.Ext(path) == ".jsonl" {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var line map[string]int64
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				t.Fatalf("%v: %q", err, scanner.Text())
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records[1:] {
+		line := make(map[string]int64)
+		for i, column := range records[0] {
+			line[column], err = strconv.ParseInt(record[i], 10, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// expectedMetrics returns the columns a metrics file should have for a turn, given the alive cells before and after it.
+func expectedMetrics(turn int, before, after []util.Cell) map[string]int64 {
+	was := make(map[util.Cell]bool)
+	for _, cell := range before {
+		was[cell] = true
+	}
+	m := map[string]int64{"turn": int64(turn), "alive": int64(len(after))}
+	for i, cell := range after {
+		if !was[cell] {
+			m["births"]++
+		}
+		delete(was, cell)
+		if i == 0 {
+			m["min_x"], m["min_y"], m["max_x"], m["max_y"] = int64(cell.X), int64(cell.Y), int64(cell.X+1), int64(cell.Y+1)
+			continue
+		}
+		if x := int64(cell.X); x < m["min_x"] {
+			m["min_x"] = x
+		} else if x >= m["max_x"] {
+			m["max_x"] = x + 1
+		}
+		if y := int64(cell.Y); y < m["min_y"] {
+			m["min_y"] = y
+		} else if y >= m["max_y"] {
+			m["max_y"] = y + 1
+		}
+	}
+	m["deaths"] = int64(len(was))
+	return m
+}
+
+// TestMetrics checks that the metrics recorded for every turn match the boards before and after it,
+// including after cells have been edited, for bounded boards on the backend selected for the tests
+// and for unbounded boards, in both formats.
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		unbounded bool
+	}{
+		{"bounded csv", "metrics.csv", false},
+		{"bounded jsonl", "metrics.jsonl", false},
+		{"unbounded jsonl", "metrics.jsonl", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "gol")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			soup := gol.Soup{Seed: 5, Density: 0.4, BoxWidth: 12, BoxHeight: 10}
+			p := gol.Params{ImageWidth: 24, ImageHeight: 20, Threads: 2, Soup: &soup, Unbounded: test.unbounded}
+			reference, err := gol.NewSimulation(gol.WithParams(p))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reference.Close()
+			p.Metrics = filepath.Join(dir, "out", test.file)
+			opts := []gol.Option{gol.WithParams(p)}
+			if !test.unbounded {
+				opts = append(opts, gol.WithBackend(testBackend(t)))
+			}
+			sim, err := gol.NewSimulation(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expected []map[string]int64
+			step := func(turns int) {
+				for i := 0; i < turns; i++ {
+					before := reference.AliveCells()
+					if err := reference.Step(1); err != nil {
+						t.Fatal(err)
+					}
+					expected = append(expected, expectedMetrics(reference.Turn(), before, reference.AliveCells()))
+				}
+				if err := sim.Step(turns); err != nil {
+					t.Fatal(err)
+				}
+			}
+			step(20)
+			edit := []util.Cell{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}
+			if err := reference.Flip(edit...); err != nil {
+				t.Fatal(err)
+			}
+			if err := sim.Flip(edit...); err != nil {
+				t.Fatal(err)
+			}
+			step(20)
+			if err := sim.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := readMetrics(t, p.Metrics)
+			if len(lines) != len(expected) {
+				t.Fatalf("expected %v lines, got %v", len(expected), len(lines))
+			}
+			for i, line := range lines {
+				if line["duration_ns"] < 0 {
+					t.Errorf("turn %v took %vns", line["turn"], line["duration_ns"])
+				}
+				delete(line, "duration_ns")
+				for column, value := range expected[i] {
+					if line[column] != value {
+						t.Fatalf("expected %v\ngot %v", expected[i], line)
+					}
+				}
+			}
+		})
+	}
+
+	p := gol.Params{ImageWidth: 16, ImageHeight: 16, Threads: 1, Metrics: "metrics.txt"}
+	if _, err := gol.NewSimulation(gol.WithParams(p)); !errors.Is(err, gol.ErrInvalidParams) {
+		t.Errorf("expected %v for metrics.txt, got %v", gol.ErrInvalidParams, err)
+	}
+}
@@ -1,52 +0,0 @@
-package main
-
-import (
-	"flag"
-	"fmt"
-	"runtime"
-
-	"uk.ac.bris.cs/gameoflife/gol"
-	"uk.ac.bris.cs/gameoflife/sdl"
-)
-
-// main is the function called when starting Game of Life with 'go run .'
-func main() {
-	runtime.LockOSThread()
-	var params gol.Params
-
-	flag.IntVar(
-		&params.Threads,
-		"t",
-		8,
-		"Specify the number of worker threads to use. Defaults to 8.")
-
-	flag.IntVar(
-		&params.ImageWidth,
-		"w",
-		512,
-		"Specify the width of the image. Defaults to 512.")
-
-	flag.IntVar(
-		&params.ImageHeight,
-		"h",
-		512,
-		"Specify the height of the image. Defaults to 512.")
-
-	flag.IntVar(
-		&params.Turns,
-		"turns",
-		10000,
-		"Specify the number of turns to process. Defaults to 10000.")
-
-	flag.Parse()
-
-	fmt.Println("Threads:", params.Threads)
-	fmt.Println("Width:", params.ImageWidth)
-	fmt.Println("Height:", params.ImageHeight)
-
-	keyPresses := make(chan rune, 10)
-	events := make(chan gol.Event, 1000)
-
-	gol.Run(params, events, keyPresses)
-	sdl.Start(params, events, keyPresses)
-}
@@ -1,7 +0,0 @@
-package util
-
-func Check(e error) {
-	if e != nil {
-		panic(e)
-	}
-}
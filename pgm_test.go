@@ -1,18 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"testing"
+
 	"uk.ac.bris.cs/gameoflife/gol"
 	"uk.ac.bris.cs/gameoflife/util"
 )
 
-// Pgm tests 16x16, 64x64 and 512x512 image output files on 0, 1 and 100 turns using 1-16 worker threads.
+// Pgm tests 16x16, 64x64, 512x512 and non-square image output files on 0, 1 and 100 turns using 1-16 worker threads.
 func TestPgm(t *testing.T) {
 	tests := []gol.Params{
 		{ImageWidth: 16, ImageHeight: 16},
 		{ImageWidth: 64, ImageHeight: 64},
 		{ImageWidth: 512, ImageHeight: 512},
+		{ImageWidth: 64, ImageHeight: 16},
+		{ImageWidth: 16, ImageHeight: 64},
+		{ImageWidth: 100, ImageHeight: 37},
+		{ImageWidth: 3, ImageHeight: 40},
+		{ImageWidth: 40, ImageHeight: 3},
 	}
 	for _, p := range tests {
 		for _, turns := range []int{0, 1, 100} {
@@ -41,3 +49,15 @@ func TestPgm(t *testing.T) {
 		}
 	}
 }
+
+// TestReadPgmHeaderLimit checks that a header without any whitespace is rejected after its first few bytes,
+// rather than once the whole image has been read.
+func TestReadPgmHeaderLimit(t *testing.T) {
+	r := bytes.NewReader(append([]byte("P5\n"), bytes.Repeat([]byte{'1'}, 1<<20)...))
+	if _, _, err := util.ReadPgmHeader(r); !errors.Is(err, util.ErrBadPgm) {
+		t.Fatalf("expected %v, got %v", util.ErrBadPgm, err)
+	}
+	if read := int(r.Size()) - r.Len(); read > 64 {
+		t.Errorf("read %v bytes of a header field", read)
+	}
+}
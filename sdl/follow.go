@@ -0,0 +1,81 @@
+package sdl
+
+import (
+	"image"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// follower moves the window over an unbounded board so that it keeps showing the alive cells as they spread.
+// The window shows the cells of the board from origin up to origin plus the size of the window.
+type follower struct {
+	origin image.Point
+	// alive holds every alive cell of the board and the turn it was born at,
+	// so the window can be redrawn with the same ages when it moves.
+	alive map[util.Cell]int32
+}
+
+func newFollower() *follower {
+	return &follower{alive: make(map[util.Cell]int32)}
+}
+
+// inView returns the cell of the window showing a cell of the board, and whether the cell is in view.
+func (f *follower) inView(w *Window, cell util.Cell) (util.Cell, bool) {
+	x, y := cell.X-f.origin.X, cell.Y-f.origin.Y
+	return util.Cell{X: x, Y: y}, x >= 0 && y >= 0 && x < int(w.Width) && y < int(w.Height)
+}
+
+// flip records that a cell of the board flipped, and flips it in the window if it is in view.
+func (f *follower) flip(w *Window, cell util.Cell) {
+	if _, ok := f.alive[cell]; ok {
+		delete(f.alive, cell)
+	} else {
+		f.alive[cell] = w.turn
+	}
+	if c, ok := f.inView(w, cell); ok {
+		w.FlipPixel(c.X, c.Y)
+	}
+}
+
+// follow centres the window on the alive cells when some of them have left it.
+// When they no longer fit in the window, it only moves once their centre is a quarter of the window away,
+// so that a growing pattern does not redraw the window every turn. It returns true if the window moved.
+func (f *follower) follow(w *Window, bounds image.Rectangle) bool {
+	size := image.Pt(int(w.Width), int(w.Height))
+	view := image.Rectangle{Min: f.origin, Max: f.origin.Add(size)}
+	if bounds.Empty() || bounds.In(view) {
+		return false
+	}
+	centre := bounds.Min.Add(bounds.Max).Div(2)
+	offset := centre.Sub(view.Min.Add(view.Max).Div(2))
+	fits := bounds.Dx() <= size.X && bounds.Dy() <= size.Y
+	if !fits && absInt(offset.X) < size.X/4 && absInt(offset.Y) < size.Y/4 {
+		return false
+	}
+
+	f.origin = centre.Sub(size.Div(2))
+	w.ClearPixels()
+	for cell, born := range f.alive {
+		if c, ok := f.inView(w, cell); ok {
+			w.SetPixelSince(c.X, c.Y, born)
+		}
+	}
+	return true
+}
+
+// board returns the cell of the board shown by a cell of the window.
+func (f *follower) board(cell util.Cell) util.Cell {
+	return util.Cell{X: cell.X + f.origin.X, Y: cell.Y + f.origin.Y}
+}
+
+// population returns the number of alive cells on the board, including those out of view.
+func (f *follower) population() int {
+	return len(f.alive)
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
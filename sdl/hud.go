@@ -0,0 +1,91 @@
+package sdl
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+// rateInterval is how often the turns per second shown on the HUD are recalculated.
+const rateInterval = time.Second
+
+// hud keeps track of the statistics shown in the on-screen overlay.
+type hud struct {
+	visible bool
+	turn    int
+	alive   int
+	state   gol.State
+	rate    float64
+	palette string
+	// bounds is the region of an unbounded board with alive cells in it, shown once one has been reported.
+	bounds    image.Rectangle
+	unbounded bool
+
+	// rateTurn and rateTime are the turn and time the current rate measurement started at.
+	rateTurn int
+	rateTime time.Time
+}
+
+func newHud() *hud {
+	return &hud{
+		visible:  true,
+		state:    gol.Executing,
+		rateTime: time.Now(),
+	}
+}
+
+// update records the information carried by a Game of Life event.
+func (h *hud) update(event gol.Event) {
+	switch e := event.(type) {
+	case gol.AliveCellsCount:
+		h.alive = e.CellsCount
+	case gol.StateChange:
+		h.state = e.NewState
+		if e.NewState != gol.Executing {
+			h.rate = 0
+		}
+		h.rateTurn = e.CompletedTurns
+		h.rateTime = time.Now()
+	case gol.FinalTurnComplete:
+		h.alive = len(e.Alive)
+	case gol.BoundsChanged:
+		h.bounds = e.Bounds
+		h.unbounded = true
+	case gol.TurnComplete:
+		if e.CompletedTurns < h.turn {
+			// A replay has gone back, so the rate starts being measured again.
+			h.turn = e.CompletedTurns
+			h.rateTurn = h.turn
+			h.rateTime = time.Now()
+		}
+	}
+	if turn := event.GetCompletedTurns(); turn > h.turn {
+		h.turn = turn
+	}
+	if elapsed := time.Since(h.rateTime); elapsed >= rateInterval {
+		h.rate = float64(h.turn-h.rateTurn) / elapsed.Seconds()
+		h.rateTurn = h.turn
+		h.rateTime = time.Now()
+	}
+}
+
+// lines returns the text of the overlay, or nothing if it is hidden.
+func (h *hud) lines() []string {
+	if !h.visible {
+		return nil
+	}
+	lines := []string{
+		fmt.Sprintf("Turn  %v", h.turn),
+		fmt.Sprintf("Alive %v", h.alive),
+		fmt.Sprintf("Rate  %.1f/s", h.rate),
+		fmt.Sprintf("State %v", h.state),
+		fmt.Sprintf("Rule  %v", gol.Rule),
+		fmt.Sprintf("Theme %v", h.palette),
+	}
+	if h.unbounded {
+		lines = append(lines, fmt.Sprintf("Box   %vx%v at %v,%v", h.bounds.Dx(), h.bounds.Dy(), h.bounds.Min.X, h.bounds.Min.Y))
+	}
+	return lines
+}
@@ -0,0 +1,180 @@
+package sdl
+
+import (
+	"fmt"
+	"github.com/veandco/go-sdl2/sdl"
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+func Start(p gol.Params, events <-chan gol.Event, keyPresses chan<- rune, edits chan<- util.Cell) {
+	w := NewWindow(int32(p.ImageWidth), int32(p.ImageHeight))
+	h := newHud()
+	h.palette = w.Palette()
+	w.SetOverlay(h.lines())
+
+	// An unbounded board is drawn through a follower, which moves the window along with the alive cells.
+	var f *follower
+	if p.Unbounded {
+		f = newFollower()
+	}
+
+	// Cells already toggled by the current click and drag, so each one is only flipped once per stroke.
+	var stroke map[util.Cell]bool
+	edit := func(x, y int32) {
+		cell, ok := w.CellAt(x, y)
+		if !ok || stroke[cell] {
+			return
+		}
+		edited := cell
+		if f != nil {
+			edited = f.board(cell)
+		}
+		// The window never waits for the simulation, which does not read edits while it is busy.
+		// A dropped edit is left out of the stroke, so dragging over the cell again retries it.
+		select {
+		case edits <- edited:
+			stroke[cell] = true
+		default:
+		}
+	}
+
+sdlLoop:
+	for {
+		event := w.PollEvent()
+		if event != nil {
+			switch e := event.(type) {
+			case *sdl.KeyboardEvent:
+				switch e.Keysym.Sym {
+				case sdl.K_p:
+					keyPresses <- 'p'
+				case sdl.K_s:
+					keyPresses <- 's'
+				case sdl.K_q:
+					keyPresses <- 'q'
+				case sdl.K_k:
+					keyPresses <- 'k'
+				case sdl.K_PERIOD:
+					keyPresses <- '.'
+				case sdl.K_n:
+					keyPresses <- 'n'
+				case sdl.K_LEFTBRACKET:
+					keyPresses <- '['
+				case sdl.K_RIGHTBRACKET:
+					keyPresses <- ']'
+				case sdl.K_u:
+					keyPresses <- 'u'
+				case sdl.K_g:
+					keyPresses <- 'g'
+				case sdl.K_COMMA:
+					keyPresses <- ','
+				case sdl.K_0, sdl.K_1, sdl.K_2, sdl.K_3, sdl.K_4, sdl.K_5, sdl.K_6, sdl.K_7, sdl.K_8, sdl.K_9:
+					keyPresses <- rune('0' + e.Keysym.Sym - sdl.K_0)
+				case sdl.K_UP:
+					w.Pan(0, -1)
+					w.RenderFrame()
+				case sdl.K_DOWN:
+					w.Pan(0, 1)
+					w.RenderFrame()
+				case sdl.K_LEFT:
+					w.Pan(-1, 0)
+					w.RenderFrame()
+				case sdl.K_RIGHT:
+					w.Pan(1, 0)
+					w.RenderFrame()
+				case sdl.K_EQUALS, sdl.K_PLUS, sdl.K_KP_PLUS:
+					w.ZoomCentre(1)
+					w.RenderFrame()
+				case sdl.K_MINUS, sdl.K_KP_MINUS:
+					w.ZoomCentre(-1)
+					w.RenderFrame()
+				case sdl.K_f:
+					w.ResetView()
+					w.RenderFrame()
+				case sdl.K_c:
+					h.palette = w.NextPalette()
+					w.SetOverlay(h.lines())
+					w.RenderFrame()
+				case sdl.K_h:
+					h.visible = !h.visible
+					w.SetOverlay(h.lines())
+					w.RenderFrame()
+				}
+			case *sdl.MouseWheelEvent:
+				steps := e.Y
+				if e.Direction == sdl.MOUSEWHEEL_FLIPPED {
+					steps = -steps
+				}
+				x, y, _ := sdl.GetMouseState()
+				w.Zoom(steps, x, y)
+				w.RenderFrame()
+			case *sdl.WindowEvent:
+				if e.Event == sdl.WINDOWEVENT_SIZE_CHANGED {
+					w.Resized()
+					w.RenderFrame()
+				}
+			case *sdl.MouseButtonEvent:
+				if e.Button != sdl.BUTTON_LEFT {
+					break
+				}
+				if e.Type == sdl.MOUSEBUTTONDOWN {
+					stroke = make(map[util.Cell]bool)
+					edit(e.X, e.Y)
+				} else {
+					stroke = nil
+				}
+			case *sdl.MouseMotionEvent:
+				if stroke != nil {
+					edit(e.X, e.Y)
+				}
+			}
+		}
+		select {
+		case event, ok := <-events:
+			if !ok {
+				w.Destroy()
+				break sdlLoop
+			}
+			h.update(event)
+			switch e := event.(type) {
+			case gol.CellFlipped:
+				if f != nil {
+					f.flip(w, e.Cell)
+				} else {
+					w.FlipPixel(e.Cell.X, e.Cell.Y)
+				}
+			case gol.CellsFlipped:
+				if f != nil {
+					for _, cell := range e.Cells {
+						f.flip(w, cell)
+					}
+				} else {
+					w.FlipPixels(e.Cells)
+				}
+			case gol.BoundsChanged:
+				if f != nil {
+					f.follow(w, e.Bounds)
+				}
+			case gol.TurnComplete:
+				w.SetTurn(e.CompletedTurns)
+				h.alive = w.Population()
+				if f != nil {
+					h.alive = f.population()
+				}
+				w.SetOverlay(h.lines())
+				w.RenderFrame()
+			case gol.StateChange:
+				fmt.Printf("Completed Turns %-8v%v\n", event.GetCompletedTurns(), event)
+				w.SetOverlay(h.lines())
+				w.RenderFrame()
+			default:
+				if len(event.String()) > 0 {
+					fmt.Printf("Completed Turns %-8v%v\n", event.GetCompletedTurns(), event)
+				}
+			}
+		default:
+			break
+		}
+	}
+
+}
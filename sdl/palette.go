@@ -0,0 +1,93 @@
+package sdl
+
+const (
+	// youngAge is the age in turns at which a cell is drawn in the young colour.
+	youngAge = 8
+	// oldAge is the age in turns from which a cell is drawn in the old colour.
+	oldAge = 100
+	// trailLength is the number of turns it takes for the trail of a dead cell to fade away.
+	trailLength = 16
+)
+
+type colour struct {
+	r, g, b uint8
+}
+
+// palette describes how cells are coloured depending on how long they have been alive or dead for.
+type palette struct {
+	name string
+	// newborn, young and old are the colours of alive cells, blended between depending on their age.
+	newborn, young, old colour
+	// trail is the colour of a cell that has just died, which then fades into the background.
+	trail      colour
+	background colour
+}
+
+// palettes are the colour schemes that can be cycled through. The first one is the default.
+var palettes = []palette{
+	{
+		name:       "classic",
+		newborn:    colour{0xFF, 0xFF, 0xFF},
+		young:      colour{0xFF, 0xFF, 0xFF},
+		old:        colour{0xFF, 0xFF, 0xFF},
+		trail:      colour{0x00, 0x00, 0x00},
+		background: colour{0x00, 0x00, 0x00},
+	},
+	{
+		name:       "heat",
+		newborn:    colour{0xFF, 0xFF, 0xC8},
+		young:      colour{0xFF, 0xAA, 0x00},
+		old:        colour{0xC8, 0x1E, 0x1E},
+		trail:      colour{0x3C, 0x3C, 0x8C},
+		background: colour{0x00, 0x00, 0x00},
+	},
+	{
+		name:       "ocean",
+		newborn:    colour{0xDC, 0xFF, 0xFF},
+		young:      colour{0x00, 0xBE, 0xFF},
+		old:        colour{0x28, 0x3C, 0xC8},
+		trail:      colour{0x00, 0x50, 0x50},
+		background: colour{0x00, 0x00, 0x10},
+	},
+	{
+		name:       "forest",
+		newborn:    colour{0xE6, 0xFF, 0x78},
+		young:      colour{0x3C, 0xC8, 0x3C},
+		old:        colour{0x14, 0x64, 0x28},
+		trail:      colour{0x5A, 0x3C, 0x14},
+		background: colour{0x00, 0x00, 0x00},
+	},
+}
+
+// blend returns the colour step/steps of the way from a to b.
+func blend(a, b colour, step, steps int32) colour {
+	mix := func(x, y uint8) uint8 {
+		return uint8((int32(x)*(steps-step) + int32(y)*step) / steps)
+	}
+	return colour{mix(a.r, b.r), mix(a.g, b.g), mix(a.b, b.b)}
+}
+
+// alive returns the colour of a cell that has been alive for the given number of turns.
+func (p palette) alive(age int32) colour {
+	switch {
+	case age <= 1:
+		return p.newborn
+	case age < youngAge:
+		return blend(p.newborn, p.young, age-1, youngAge-1)
+	case age < oldAge:
+		return blend(p.young, p.old, age-youngAge, oldAge-youngAge)
+	default:
+		return p.old
+	}
+}
+
+// dead returns the colour of a cell that has been dead for the given number of turns.
+func (p palette) dead(age int32) colour {
+	switch {
+	case age <= 0:
+		return p.trail
+	case age >= trailLength:
+		return p.background
+	}
+	return blend(p.trail, p.background, age, trailLength)
+}
@@ -0,0 +1,416 @@
+package sdl
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+const (
+	// maxScale is the largest number of screen pixels a single cell can be zoomed to.
+	maxScale = 64
+	// minimapSize is the length of the longest side of the minimap overlay.
+	minimapSize = 128
+	// minimapMargin is the gap between the minimap and the corner of the window.
+	minimapMargin = 8
+	// overlayPadding is the space between the HUD text and the edge of its background.
+	overlayPadding = 6
+)
+
+type Window struct {
+	Width, Height int32
+	window        *sdl.Window
+	renderer      *sdl.Renderer
+	texture       *sdl.Texture
+	// pixels holds the colours of the cells in view, uploaded to the texture on every frame.
+	pixels []byte
+
+	// scale is the integer number of screen pixels used for every cell.
+	scale int32
+	// viewX and viewY are the coordinates of the top left cell currently in view.
+	viewX, viewY int32
+
+	// alive holds the state of every cell, and changed the turn at which it last flipped.
+	// Together they give the age of alive cells and the trails of dead ones.
+	alive   []bool
+	changed []int32
+	// turn is the most recently completed turn that has been drawn.
+	turn int32
+	// population is the number of alive cells drawn.
+	population int
+	// palette is the index of the colour scheme in use.
+	palette int
+	// overlay is the text drawn in the top left corner on top of the board.
+	overlay []string
+}
+
+// neverChanged marks cells that have not flipped since the board was loaded, so no trail is drawn for them.
+const neverChanged = -1 << 30
+
+func filterEvent(e sdl.Event, userdata interface{}) bool {
+	switch e.GetType() {
+	case sdl.KEYDOWN, sdl.QUIT, sdl.MOUSEBUTTONDOWN, sdl.MOUSEBUTTONUP, sdl.MOUSEMOTION, sdl.MOUSEWHEEL, sdl.WINDOWEVENT:
+		return true
+	}
+	return false
+}
+
+// fitScale returns the largest integer scale at which a board of the given size fits in the given area.
+func fitScale(width, height, areaWidth, areaHeight int32) int32 {
+	scale := areaWidth / width
+	if s := areaHeight / height; s < scale {
+		scale = s
+	}
+	if scale < 1 {
+		scale = 1
+	}
+	if scale > maxScale {
+		scale = maxScale
+	}
+	return scale
+}
+
+// screenArea returns how much of the screen a new window is allowed to cover.
+func screenArea() (int32, int32) {
+	bounds, err := sdl.GetDisplayBounds(0)
+	if err != nil || bounds.W == 0 || bounds.H == 0 {
+		return 1024, 768
+	}
+	return bounds.W * 3 / 4, bounds.H * 3 / 4
+}
+
+func NewWindow(width, height int32) *Window {
+	err := sdl.Init(sdl.INIT_EVERYTHING)
+	util.Check(err)
+
+	// Small boards are upscaled by an integer factor, large ones are shown through a viewport.
+	areaWidth, areaHeight := screenArea()
+	scale := fitScale(width, height, areaWidth, areaHeight)
+	windowWidth, windowHeight := width*scale, height*scale
+	if windowWidth > areaWidth {
+		windowWidth = areaWidth
+	}
+	if windowHeight > areaHeight {
+		windowHeight = areaHeight
+	}
+
+	window, err := sdl.CreateWindow("GOL GUI", sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED, windowWidth, windowHeight, sdl.WINDOW_SHOWN|sdl.WINDOW_RESIZABLE)
+	util.Check(err)
+	renderer, err := sdl.CreateRenderer(window, -1, sdl.WINDOW_SHOWN)
+	util.Check(err)
+	// Nearest neighbour sampling keeps the upscaled cells sharp.
+	sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "nearest")
+	texture, err := renderer.CreateTexture(sdl.PIXELFORMAT_ARGB8888, sdl.TEXTUREACCESS_STATIC, width, height)
+	util.Check(err)
+
+	sdl.SetEventFilterFunc(filterEvent, nil)
+	w := &Window{
+		Width:    width,
+		Height:   height,
+		window:   window,
+		renderer: renderer,
+		texture:  texture,
+		scale:    scale,
+		alive:    make([]bool, width*height),
+		changed:  make([]int32, width*height),
+	}
+	w.ClearPixels()
+	return w
+}
+
+func (w *Window) Destroy() {
+	err := w.texture.Destroy()
+	util.Check(err)
+	err = w.renderer.Destroy()
+	util.Check(err)
+	err = w.window.Destroy()
+	util.Check(err)
+	sdl.Quit()
+}
+
+// outputSize returns the size of the drawable area of the window in pixels.
+func (w *Window) outputSize() (int32, int32) {
+	width, height, err := w.renderer.GetOutputSize()
+	util.Check(err)
+	return width, height
+}
+
+// visible returns the region of the board that is currently in view, in cells.
+func (w *Window) visible() sdl.Rect {
+	outWidth, outHeight := w.outputSize()
+	view := sdl.Rect{X: w.viewX, Y: w.viewY, W: outWidth / w.scale, H: outHeight / w.scale}
+	if view.W > w.Width {
+		view.W = w.Width
+	}
+	if view.H > w.Height {
+		view.H = w.Height
+	}
+	if view.W < 1 {
+		view.W = 1
+	}
+	if view.H < 1 {
+		view.H = 1
+	}
+	return view
+}
+
+// destination returns where in the window the visible region of the board is drawn.
+// The board is centred when the window is larger than it.
+func (w *Window) destination(view sdl.Rect) sdl.Rect {
+	outWidth, outHeight := w.outputSize()
+	dst := sdl.Rect{W: view.W * w.scale, H: view.H * w.scale}
+	dst.X = (outWidth - dst.W) / 2
+	dst.Y = (outHeight - dst.H) / 2
+	return dst
+}
+
+// clampView keeps the viewport inside the board.
+func (w *Window) clampView() {
+	view := w.visible()
+	if w.viewX > w.Width-view.W {
+		w.viewX = w.Width - view.W
+	}
+	if w.viewY > w.Height-view.H {
+		w.viewY = w.Height - view.H
+	}
+	if w.viewX < 0 {
+		w.viewX = 0
+	}
+	if w.viewY < 0 {
+		w.viewY = 0
+	}
+}
+
+// Zoom changes the scale by the given number of steps, keeping the cell under the window position x, y in place.
+func (w *Window) Zoom(steps int32, x, y int32) {
+	scale := w.scale + steps
+	if scale < 1 {
+		scale = 1
+	}
+	if scale > maxScale {
+		scale = maxScale
+	}
+	if scale == w.scale {
+		return
+	}
+	dst := w.destination(w.visible())
+	cellX := w.viewX + (x-dst.X)/w.scale
+	cellY := w.viewY + (y-dst.Y)/w.scale
+	w.scale = scale
+	dst = w.destination(w.visible())
+	w.viewX = cellX - (x-dst.X)/w.scale
+	w.viewY = cellY - (y-dst.Y)/w.scale
+	w.clampView()
+}
+
+// ZoomCentre zooms around the middle of the window.
+func (w *Window) ZoomCentre(steps int32) {
+	outWidth, outHeight := w.outputSize()
+	w.Zoom(steps, outWidth/2, outHeight/2)
+}
+
+// Pan moves the viewport by an eighth of its size for every step in each direction.
+func (w *Window) Pan(dx, dy int32) {
+	view := w.visible()
+	w.viewX += dx * maxInt32(view.W/8, 1)
+	w.viewY += dy * maxInt32(view.H/8, 1)
+	w.clampView()
+}
+
+// ResetView picks the largest integer scale that fits the whole board in the window, or 1 if it cannot fit.
+func (w *Window) ResetView() {
+	outWidth, outHeight := w.outputSize()
+	w.scale = fitScale(w.Width, w.Height, outWidth, outHeight)
+	w.viewX, w.viewY = 0, 0
+	w.clampView()
+}
+
+// Resized should be called when the size of the window changes.
+func (w *Window) Resized() {
+	w.clampView()
+}
+
+func (w *Window) RenderFrame() {
+	view := w.visible()
+	// Only the part of the board that is in view is coloured and uploaded to the texture.
+	w.colourPixels(view)
+	err := w.texture.Update(&view, w.pixels, int(view.W*4))
+	util.Check(err)
+	err = w.renderer.SetDrawColor(0, 0, 0, 0xFF)
+	util.Check(err)
+	err = w.renderer.Clear()
+	util.Check(err)
+	dst := w.destination(view)
+	err = w.renderer.Copy(w.texture, &view, &dst)
+	util.Check(err)
+	if view.W < w.Width || view.H < w.Height {
+		w.renderMinimap(view)
+	}
+	if len(w.overlay) > 0 {
+		w.renderOverlay()
+	}
+	w.renderer.Present()
+}
+
+// renderOverlay draws the overlay text on a translucent background in the top left corner.
+func (w *Window) renderOverlay() {
+	width, height := textSize(w.overlay)
+	background := sdl.Rect{X: minimapMargin, Y: minimapMargin, W: width + 2*overlayPadding, H: height + 2*overlayPadding}
+
+	util.Check(w.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND))
+	util.Check(w.renderer.SetDrawColor(0x20, 0x20, 0x20, 0xC0))
+	util.Check(w.renderer.FillRect(&background))
+	util.Check(w.renderer.SetDrawColor(0x40, 0xFF, 0x40, 0xFF))
+	util.Check(drawText(w.renderer, background.X+overlayPadding, background.Y+overlayPadding, w.overlay))
+	util.Check(w.renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE))
+}
+
+// SetOverlay replaces the text drawn on top of the board. It is shown from the next call to RenderFrame.
+func (w *Window) SetOverlay(lines []string) {
+	w.overlay = lines
+}
+
+// Population returns the number of alive cells currently drawn.
+func (w *Window) Population() int {
+	return w.population
+}
+
+// colourPixels fills the pixel buffer with the colours of the cells in view.
+func (w *Window) colourPixels(view sdl.Rect) {
+	size := int(view.W * view.H * 4)
+	if cap(w.pixels) < size {
+		w.pixels = make([]byte, size)
+	}
+	w.pixels = w.pixels[:size]
+
+	p := palettes[w.palette]
+	i := 0
+	for y := view.Y; y < view.Y+view.H; y++ {
+		for x := view.X; x < view.X+view.W; x++ {
+			cell := y*w.Width + x
+			var c colour
+			if w.alive[cell] {
+				c = p.alive(w.turn - w.changed[cell])
+			} else {
+				c = p.dead(w.turn - w.changed[cell])
+			}
+			// ARGB8888 is stored as B, G, R, A in memory.
+			w.pixels[i+0] = c.b
+			w.pixels[i+1] = c.g
+			w.pixels[i+2] = c.r
+			w.pixels[i+3] = 0xFF
+			i += 4
+		}
+	}
+}
+
+// renderMinimap draws an outline of the whole board in the top right corner with the viewport marked inside it.
+func (w *Window) renderMinimap(view sdl.Rect) {
+	outWidth, _ := w.outputSize()
+	longest := maxInt32(w.Width, w.Height)
+	board := sdl.Rect{
+		W: maxInt32(w.Width*minimapSize/longest, 1),
+		H: maxInt32(w.Height*minimapSize/longest, 1),
+	}
+	board.X = outWidth - board.W - minimapMargin
+	board.Y = minimapMargin
+	viewport := sdl.Rect{
+		X: board.X + view.X*board.W/w.Width,
+		Y: board.Y + view.Y*board.H/w.Height,
+		W: maxInt32(view.W*board.W/w.Width, 1),
+		H: maxInt32(view.H*board.H/w.Height, 1),
+	}
+
+	util.Check(w.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND))
+	util.Check(w.renderer.SetDrawColor(0x20, 0x20, 0x20, 0xC0))
+	util.Check(w.renderer.FillRect(&board))
+	util.Check(w.renderer.SetDrawColor(0xFF, 0xFF, 0xFF, 0xFF))
+	util.Check(w.renderer.DrawRect(&board))
+	util.Check(w.renderer.SetDrawColor(0xFF, 0x40, 0x40, 0xFF))
+	util.Check(w.renderer.DrawRect(&viewport))
+	util.Check(w.renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE))
+}
+
+func (w *Window) PollEvent() sdl.Event {
+	return sdl.PollEvent()
+}
+
+func (w *Window) SetPixel(x, y int) {
+	if !w.alive[y*int(w.Width)+x] {
+		w.FlipPixel(x, y)
+	}
+}
+
+// SetPixelSince makes a cell alive as if it had been born at the given turn, so that a cell drawn again keeps its age.
+func (w *Window) SetPixelSince(x, y int, turn int32) {
+	w.SetPixel(x, y)
+	w.changed[y*int(w.Width)+x] = turn
+}
+
+// FlipPixel flips the state of a cell, remembering the turn it happened at for colouring.
+func (w *Window) FlipPixel(x, y int) {
+	cell := y*int(w.Width) + x
+	w.alive[cell] = !w.alive[cell]
+	w.changed[cell] = w.turn
+	if w.alive[cell] {
+		w.population++
+	} else {
+		w.population--
+	}
+}
+
+// FlipPixels flips the state of all the given cells.
+func (w *Window) FlipPixels(cells []util.Cell) {
+	for _, cell := range cells {
+		w.FlipPixel(cell.X, cell.Y)
+	}
+}
+
+// SetTurn records the most recently completed turn, which ages every cell drawn from then on.
+// Going back to an earlier turn, as a replay does when it seeks, makes cells changed after it look just changed.
+func (w *Window) SetTurn(turn int) {
+	if t := int32(turn); t < w.turn {
+		for i, changed := range w.changed {
+			if changed > t {
+				w.changed[i] = t
+			}
+		}
+	}
+	w.turn = int32(turn)
+}
+
+// NextPalette switches to the next colour scheme and returns its name.
+func (w *Window) NextPalette() string {
+	w.palette = (w.palette + 1) % len(palettes)
+	return palettes[w.palette].name
+}
+
+// Palette returns the name of the colour scheme in use.
+func (w *Window) Palette() string {
+	return palettes[w.palette].name
+}
+
+// CellAt converts a position inside the window to the cell under it.
+// The second result is false if the position lies outside of the board.
+func (w *Window) CellAt(x, y int32) (util.Cell, bool) {
+	view := w.visible()
+	dst := w.destination(view)
+	if x < dst.X || y < dst.Y || x >= dst.X+dst.W || y >= dst.Y+dst.H {
+		return util.Cell{}, false
+	}
+	return util.Cell{X: int(view.X + (x-dst.X)/w.scale), Y: int(view.Y + (y-dst.Y)/w.scale)}, true
+}
+
+func (w *Window) ClearPixels() {
+	for i := range w.alive {
+		w.alive[i] = false
+		w.changed[i] = neverChanged
+	}
+	w.population = 0
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
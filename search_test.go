@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/analysis"
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+// TestSearch checks that a soup search gives the same catalogue from the same master seed, however many threads it uses,
+// and that every object it counts is accounted for.
+func TestSearch(t *testing.T) {
+	p := analysis.SearchParams{Seed: 3, Soups: 40, Width: 16, Height: 16, Density: 0.5, Symmetry: gol.C1, MaxTurns: 5000, Threads: 1}
+	catalogue, err := analysis.Search(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Threads = 4
+	again, err := analysis.Search(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(catalogue.Objects, again.Objects) || !reflect.DeepEqual(catalogue.Unstable, again.Unstable) {
+		t.Errorf("the catalogue with 4 threads is different from the one with 1")
+	}
+
+	if len(catalogue.Objects) == 0 || catalogue.Objects[0].Name != "block" {
+		t.Fatalf("expected the block to be the most common object, got %+v", catalogue.Objects)
+	}
+	for _, entry := range catalogue.Objects {
+		if entry.Soups == 0 || entry.Count < entry.Soups || len(entry.Seeds) == 0 || len(entry.Seeds) > 3 {
+			t.Errorf("inconsistent entry %+v", entry)
+		}
+		if entry.Rare != (entry.Soups == 1) {
+			t.Errorf("%v was found in %v soups, but rare is %v", entry.Code, entry.Soups, entry.Rare)
+		}
+	}
+
+	// The first soup the blocks were found in leaves some when it is evolved on its own.
+	p.Seed = catalogue.Objects[0].Seeds[0]
+	board, err := gol.Soup{Seed: p.Seed, Density: p.Density, Symmetry: p.Symmetry}.Board(p.Width, p.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim, err := gol.NewSimulation(gol.WithBoard(board), gol.WithParams(gol.Params{Threads: 1, Turns: p.MaxTurns, UntilStable: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+	if err := sim.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	census, err := analysis.TakeCensus(sim.AliveCells(), p.Width, p.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, count := range census.Counts {
+		found = found || count.Name == "block"
+	}
+	if !found {
+		t.Errorf("expected soup %v to leave a block, got %+v", p.Seed, census.Counts)
+	}
+}
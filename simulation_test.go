@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// TestSimulationStep checks that stepping a Simulation matches the expected images, in one go and turn by turn.
+func TestSimulationStep(t *testing.T) {
+	for _, size := range []int{16, 64} {
+		p := gol.Params{ImageWidth: size, ImageHeight: size, Threads: 4}
+		expected := util.ReadAliveCells(fmt.Sprintf("check/images/%vx%vx100.pgm", size, size), size, size)
+
+		t.Run(fmt.Sprintf("%dx%d-once", size, size), func(t *testing.T) {
+			sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(testBackend(t)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sim.Close()
+			if err := sim.Step(100); err != nil {
+				t.Fatal(err)
+			}
+			if sim.Turn() != 100 {
+				t.Errorf("expected turn 100, got %v", sim.Turn())
+			}
+			assertEqualBoard(t, sim.AliveCells(), expected, p)
+		})
+
+		t.Run(fmt.Sprintf("%dx%d-each", size, size), func(t *testing.T) {
+			sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(testBackend(t)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sim.Close()
+			for turn := 0; turn < 100; turn++ {
+				if err := sim.Step(1); err != nil {
+					t.Fatal(err)
+				}
+			}
+			assertEqualBoard(t, sim.AliveCells(), expected, p)
+		})
+	}
+}
+
+// TestSimulationRun checks that Run processes the turns in the params and stops when its context is cancelled.
+func TestSimulationRun(t *testing.T) {
+	p := gol.Params{ImageWidth: 64, ImageHeight: 64, Threads: 8, Turns: 100}
+
+	t.Run("complete", func(t *testing.T) {
+		events := make(chan gol.Event, 1000)
+		sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithEvents(events), gol.WithBackend(testBackend(t)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sim.Close()
+		go func() {
+			for range events {
+			}
+		}()
+		if err := sim.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		close(events)
+		expected := util.ReadAliveCells("check/images/64x64x100.pgm", 64, 64)
+		assertEqualBoard(t, sim.AliveCells(), expected, p)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(testBackend(t)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sim.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := sim.Run(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+		if sim.Turn() != 0 {
+			t.Errorf("expected no turns to be processed, got %v", sim.Turn())
+		}
+	})
+}
+
+// TestSimulationBoard checks a Simulation started from a board, and that edits and saves change the right cells.
+func TestSimulationBoard(t *testing.T) {
+	board := make([][]byte, 6)
+	for y := range board {
+		board[y] = make([]byte, 6)
+	}
+	// A vertical blinker.
+	board[1][2], board[2][2], board[3][2] = 255, 255, 255
+
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sim, err := gol.NewSimulation(gol.WithBoard(board), gol.WithOutputDir(dir), gol.WithBackend(testBackend(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+	board[0][0] = 255
+	if sim.Board()[0][0] != 0 {
+		t.Error("the Simulation should copy the board it is given")
+	}
+	if p := sim.Params(); p.ImageWidth != 6 || p.ImageHeight != 6 {
+		t.Errorf("expected a 6x6 board, got %vx%v", p.ImageWidth, p.ImageHeight)
+	}
+
+	if err := sim.Step(1); err != nil {
+		t.Fatal(err)
+	}
+	expected := []util.Cell{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}}
+	assertEqualBoard(t, sim.AliveCells(), expected, sim.Params())
+
+	if err := sim.Flip(util.Cell{X: 1, Y: 2}, util.Cell{X: 5, Y: 5}); err != nil {
+		t.Fatal(err)
+	}
+	expected = []util.Cell{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 5, Y: 5}}
+	assertEqualBoard(t, sim.AliveCells(), expected, sim.Params())
+
+	path, err := sim.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "6x6x1.pgm" {
+		t.Errorf("expected the image to be saved as 6x6x1.pgm, got %v", filepath.Base(path))
+	}
+	assertEqualBoard(t, util.ReadAliveCells(path, 6, 6), expected, sim.Params())
+}
+
+// TestSimulationErrors checks that problems are returned as errors rather than panics.
+func TestSimulationErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "16x16.pgm"), []byte("P5\n8 8\n255\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []gol.Option
+		expected error
+	}{
+		{"no threads", []gol.Option{gol.WithParams(gol.Params{ImageWidth: 16, ImageHeight: 16})}, gol.ErrInvalidParams},
+		{"no size", []gol.Option{gol.WithParams(gol.Params{Threads: 1})}, gol.ErrInvalidParams},
+		{"negative turns", []gol.Option{gol.WithParams(gol.Params{ImageWidth: 16, ImageHeight: 16, Threads: 1, Turns: -1})}, gol.ErrInvalidParams},
+		{"negative report interval", []gol.Option{gol.WithParams(gol.Params{ImageWidth: 16, ImageHeight: 16, Threads: 1, ReportInterval: -time.Second})}, gol.ErrInvalidParams},
+		{"ragged board", []gol.Option{gol.WithBoard([][]byte{{0, 0}, {0}})}, gol.ErrInvalidParams},
+		{"missing image", []gol.Option{gol.WithParams(gol.Params{ImageWidth: 17, ImageHeight: 17, Threads: 1})}, os.ErrNotExist},
+		{"wrong size image", []gol.Option{gol.WithParams(gol.Params{ImageWidth: 16, ImageHeight: 16, Threads: 1}), gol.WithImageDir(dir)}, gol.ErrBadImage},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := gol.NewSimulation(test.opts...)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+
+	t.Run("out of bounds", func(t *testing.T) {
+		sim, err := gol.NewSimulation(gol.WithBoard([][]byte{{0, 0}, {0, 0}}), gol.WithBackend(testBackend(t)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sim.Close()
+		if err := sim.Flip(util.Cell{X: 0, Y: 0}, util.Cell{X: 2, Y: 0}); !errors.Is(err, gol.ErrOutOfBounds) {
+			t.Errorf("expected %v, got %v", gol.ErrOutOfBounds, err)
+		}
+		if len(sim.AliveCells()) != 0 {
+			t.Error("no cells should be flipped when one is out of bounds")
+		}
+		if err := sim.Step(-1); !errors.Is(err, gol.ErrInvalidParams) {
+			t.Errorf("expected %v, got %v", gol.ErrInvalidParams, err)
+		}
+	})
+}
+
+// TestPerCellEvents checks that with Params.PerCellEvents a run sends every flipped cell, including the initial ones,
+// as its own CellFlipped Event before the TurnComplete of its turn, and never a CellsFlipped Event.
+func TestPerCellEvents(t *testing.T) {
+	p := gol.Params{ImageWidth: 16, ImageHeight: 16, Threads: 2, Turns: 100, PerCellEvents: true}
+	events := make(chan gol.Event, 1000)
+	gol.Run(p, events, nil)
+
+	alive := make(map[util.Cell]bool)
+	turn := 0
+	for event := range events {
+		switch e := event.(type) {
+		case gol.CellsFlipped:
+			t.Fatalf("expected only CellFlipped Events, got %v", e)
+		case gol.CellFlipped:
+			if e.CompletedTurns != turn {
+				t.Fatalf("expected the cells flipped after turn %v, got %v", turn, e)
+			}
+			alive[e.Cell] = !alive[e.Cell]
+		case gol.TurnComplete:
+			turn = e.CompletedTurns
+		case gol.FinalTurnComplete:
+			var drawn []util.Cell
+			for cell, isAlive := range alive {
+				if isAlive {
+					drawn = append(drawn, cell)
+				}
+			}
+			if len(drawn) != len(e.Alive) || !assertEqualBoard(t, drawn, e.Alive, p) {
+				t.Fatal("the cells flipped do not add up to the final board")
+			}
+		}
+	}
+	if turn != 100 {
+		t.Errorf("expected 100 turns, got %v", turn)
+	}
+}
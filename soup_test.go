@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// TestSoup checks that soups are the same every time they are generated from the same seed,
+// have roughly the density asked for, and have the symmetry asked for.
+func TestSoup(t *testing.T) {
+	symmetries := []struct {
+		symmetry gol.Symmetry
+		// maps are the positions every cell must match on a board of the given size.
+		maps []func(x, y, size int) (int, int)
+	}{
+		{gol.C1, nil},
+		{gol.C2, []func(x, y, size int) (int, int){
+			func(x, y, size int) (int, int) { return size - 1 - x, size - 1 - y }}},
+		{gol.C4, []func(x, y, size int) (int, int){
+			func(x, y, size int) (int, int) { return size - 1 - y, x }}},
+		{gol.D2, []func(x, y, size int) (int, int){
+			func(x, y, size int) (int, int) { return size - 1 - x, y }}},
+		{gol.D4, []func(x, y, size int) (int, int){
+			func(x, y, size int) (int, int) { return size - 1 - x, y },
+			func(x, y, size int) (int, int) { return x, size - 1 - y }}},
+		{gol.D8, []func(x, y, size int) (int, int){
+			func(x, y, size int) (int, int) { return size - 1 - x, y },
+			func(x, y, size int) (int, int) { return y, x }}},
+	}
+	for _, test := range symmetries {
+		for _, size := range []int{31, 64} {
+			t.Run(fmt.Sprintf("%v-%v", test.symmetry, size), func(t *testing.T) {
+				soup := gol.Soup{Seed: 7, Density: 0.3, Symmetry: test.symmetry}
+				board, err := soup.Board(size, size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				again, _ := soup.Board(size, size)
+				alive := 0
+				for y := range board {
+					for x := range board[y] {
+						if board[y][x] != again[y][x] {
+							t.Fatalf("the soup is different at %v,%v when generated again", x, y)
+						}
+						for _, m := range test.maps {
+							if mx, my := m(x, y, size); board[y][x] != board[my][mx] {
+								t.Fatalf("%v,%v does not match %v,%v", x, y, mx, my)
+							}
+						}
+						if board[y][x] == 255 {
+							alive++
+						}
+					}
+				}
+				if density := float64(alive) / float64(size*size); density < 0.2 || density > 0.4 {
+					t.Errorf("expected a density of about 0.3, got %v", density)
+				}
+			})
+		}
+	}
+
+	for _, soup := range []gol.Soup{{Symmetry: gol.C4}, {Symmetry: gol.D8}, {Symmetry: "C3"}, {Density: 1.5},
+		{BoxWidth: 17, BoxHeight: 8}, {BoxWidth: 4}, {BoxWidth: 8, BoxHeight: 4, Symmetry: gol.C4}} {
+		if _, err := soup.Board(16, 8); !errors.Is(err, gol.ErrInvalidParams) {
+			t.Errorf("expected %v for a 16x8 %+v, got %v", gol.ErrInvalidParams, soup, err)
+		}
+	}
+}
+
+// TestSoupBox checks that a soup in a box is the soup generated on a board the size of the box,
+// placed in the centre of a board of dead cells.
+func TestSoupBox(t *testing.T) {
+	soup := gol.Soup{Seed: 3, Density: 0.5, Symmetry: gol.D4}
+	inner, err := soup.Board(6, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soup.BoxWidth, soup.BoxHeight = 6, 4
+	board, err := soup.Board(11, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := range board {
+		for x := range board[y] {
+			expected := byte(0)
+			if x >= 2 && x < 8 && y >= 2 && y < 6 {
+				expected = inner[y-2][x-2]
+			}
+			if board[y][x] != expected {
+				t.Fatalf("expected %v at %v,%v, got %v", expected, x, y, board[y][x])
+			}
+		}
+	}
+	if name := soup.String(); name != "s3-d0.5-D4-b6x4" {
+		t.Errorf("expected the soup to be named s3-d0.5-D4-b6x4, got %v", name)
+	}
+}
+
+// TestSoupSimulation checks that a Simulation can start from a soup instead of an image,
+// and that the images it saves record the soup in their name and header.
+func TestSoupSimulation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	soup := gol.Soup{Seed: 42, Density: 0.25, BoxWidth: 16, BoxHeight: 16}
+	p := gol.Params{ImageWidth: 32, ImageHeight: 32, Threads: 2, Soup: &soup}
+	sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithImageDir(filepath.Join(dir, "missing")), gol.WithOutputDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+	board, _ := soup.Board(32, 32)
+	assertEqualBoard(t, sim.AliveCells(), shifted(board, 0, 0), p)
+
+	path, err := sim.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "soup-s42-d0.25-C1-b16x16-32x32x0.pgm" {
+		t.Errorf("expected the image to be saved as soup-s42-d0.25-C1-b16x16-32x32x0.pgm, got %v", filepath.Base(path))
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("P5\n# soup s42-d0.25-C1-b16x16\n")) {
+		t.Errorf("expected the image header to record the soup, got %q", data[:32])
+	}
+	assertEqualBoard(t, util.ReadAliveCells(path, 32, 32), shifted(board, 0, 0), p)
+
+	p.Unbounded = true
+	unbounded, err := gol.NewSimulation(gol.WithParams(p), gol.WithOutputDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unbounded.Close()
+	path, err = unbounded.SaveRLE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("\n#C soup s42-d0.25-C1-b16x16\n")) {
+		t.Errorf("expected the pattern file to record the soup, got\n%s", data)
+	}
+}
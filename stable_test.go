@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// TestStabilised checks that boards are found to repeat themselves on the turn they first do, with the right period,
+// and that Run stops there when asked to.
+func TestStabilised(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		cells  []util.Cell
+		turn   int
+		period int
+	}{
+		{"block", 6, []util.Cell{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}}, 1, 1},
+		{"blinker", 6, []util.Cell{{X: 2, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 3}}, 2, 2},
+		// A glider moves by one cell every 4 turns, so it is back where it started after 4*8 turns.
+		{"glider", 8, []util.Cell{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}, 32, 32},
+		{"dying", 6, []util.Cell{{X: 2, Y: 2}}, 2, 1},
+	}
+	for _, test := range tests {
+		board := make([][]byte, test.size)
+		for y := range board {
+			board[y] = make([]byte, test.size)
+		}
+		for _, cell := range test.cells {
+			board[cell.Y][cell.X] = 255
+		}
+
+		t.Run(test.name, func(t *testing.T) {
+			events := make(chan gol.Event, 1000)
+			p := gol.Params{Threads: 2, Turns: 100, UntilStable: true}
+			sim, err := gol.NewSimulation(gol.WithBoard(board), gol.WithParams(p), gol.WithEvents(events), gol.WithBackend(testBackend(t)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sim.Close()
+			if err := sim.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if sim.Turn() != test.turn || sim.Period() != test.period {
+				t.Errorf("expected to stop at turn %v with period %v, stopped at turn %v with period %v",
+					test.turn, test.period, sim.Turn(), sim.Period())
+			}
+
+			// An edit starts the search again.
+			if err := sim.Flip(util.Cell{X: 0, Y: test.size - 1}); err != nil {
+				t.Fatal(err)
+			}
+			if sim.Period() != 0 {
+				t.Errorf("expected no period after an edit, got %v", sim.Period())
+			}
+			close(events)
+			var found []gol.Stabilised
+			for event := range events {
+				if e, ok := event.(gol.Stabilised); ok {
+					found = append(found, e)
+				}
+			}
+			if len(found) != 1 || found[0].CompletedTurns != test.turn || found[0].Period != test.period {
+				t.Errorf("expected one Stabilised Event at turn %v with period %v, got %v", test.turn, test.period, found)
+			}
+		})
+	}
+
+	// The images settle into a glider going around the board, into ash with blinkers, and into still lifes.
+	images := []struct {
+		size, turn, period int
+	}{
+		{16, 64, 64},
+		{64, 1577, 2},
+		{128, 6, 1},
+	}
+	for _, image := range images {
+		t.Run(fmt.Sprintf("%vx%v", image.size, image.size), func(t *testing.T) {
+			p := gol.Params{ImageWidth: image.size, ImageHeight: image.size, Threads: 8, Turns: 10000, UntilStable: true}
+			sim, err := gol.NewSimulation(gol.WithParams(p), gol.WithBackend(testBackend(t)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sim.Close()
+			if err := sim.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if sim.Turn() != image.turn || sim.Period() != image.period {
+				t.Errorf("expected to stop at turn %v with period %v, stopped at turn %v with period %v",
+					image.turn, image.period, sim.Turn(), sim.Period())
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+)
+
+// TestStateChange checks the sequence of StateChange events sent for every key command on a 16x16 image.
+func TestStateChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		turns    int
+		keys     []rune
+		expected []gol.State
+	}{
+		{
+			name:     "no keys",
+			turns:    1,
+			expected: []gol.State{gol.Loading, gol.Executing, gol.Finished, gol.Saving, gol.Quitting},
+		},
+		{
+			name:     "q",
+			turns:    100000000,
+			keys:     []rune{'q'},
+			expected: []gol.State{gol.Loading, gol.Executing, gol.Saving, gol.Quitting},
+		},
+		{
+			name:     "s",
+			turns:    100000000,
+			keys:     []rune{'s', 'q'},
+			expected: []gol.State{gol.Loading, gol.Executing, gol.Saving, gol.Executing, gol.Saving, gol.Quitting},
+		},
+		{
+			name:     "p",
+			turns:    100000000,
+			keys:     []rune{'p', 'p', 'q'},
+			expected: []gol.State{gol.Loading, gol.Executing, gol.Paused, gol.Executing, gol.Saving, gol.Quitting},
+		},
+		{
+			name:     "s while paused",
+			turns:    100000000,
+			keys:     []rune{'p', 's', 'p', 'q'},
+			expected: []gol.State{gol.Loading, gol.Executing, gol.Paused, gol.Saving, gol.Paused, gol.Executing, gol.Saving, gol.Quitting},
+		},
+		{
+			name:     "q while paused",
+			turns:    100000000,
+			keys:     []rune{'p', 'q'},
+			expected: []gol.State{gol.Loading, gol.Executing, gol.Paused, gol.Saving, gol.Quitting},
+		},
+		{
+			name:     "step while paused",
+			turns:    100000000,
+			keys:     []rune{'p', '.', '5', 'n', 'p', 'q'},
+			expected: []gol.State{gol.Loading, gol.Executing, gol.Paused, gol.Executing, gol.Saving, gol.Quitting},
+		},
+	}
+	for _, test := range tests {
+		p := gol.Params{
+			Turns:       test.turns,
+			Threads:     4,
+			ImageWidth:  16,
+			ImageHeight: 16,
+		}
+		t.Run(test.name, func(t *testing.T) {
+			keyPresses := make(chan rune, len(test.keys))
+			for _, key := range test.keys {
+				keyPresses <- key
+			}
+			events := make(chan gol.Event)
+			gol.Run(p, events, keyPresses)
+			var states []gol.State
+			for event := range events {
+				switch e := event.(type) {
+				case gol.StateChange:
+					states = append(states, e.NewState)
+				}
+			}
+			if fmt.Sprint(states) != fmt.Sprint(test.expected) {
+				t.Errorf("expected states %v, got %v", test.expected, states)
+			}
+		})
+	}
+}
+
+// TestStepInterrupted checks that keys are still handled while turns typed before 'n' are being stepped,
+// at the slowest rate where stepping them all would take hours: 'q' quits, and 'p' resumes without finishing them.
+func TestStepInterrupted(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     string
+		expected []gol.State
+	}{
+		{"q", "q", []gol.State{gol.Loading, gol.Executing, gol.Paused, gol.Saving, gol.Quitting}},
+		{"p", "pq", []gol.State{gol.Loading, gol.Executing, gol.Paused, gol.Executing, gol.Saving, gol.Quitting}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := gol.Params{Turns: 100000000, Threads: 4, ImageWidth: 16, ImageHeight: 16}
+			keyPresses := make(chan rune, 20)
+			// Slow down to the slowest rate, then step 9999 turns.
+			for _, key := range "p[[[[[[[[[[[[[9999n" {
+				keyPresses <- key
+			}
+			events := make(chan gol.Event, 100)
+			gol.Run(p, events, keyPresses)
+
+			var states []gol.State
+			timeout := time.After(10 * time.Second)
+			turn := 0
+			pressed := false
+			for done := false; !done; {
+				select {
+				case event, ok := <-events:
+					if !ok {
+						done = true
+						break
+					}
+					switch e := event.(type) {
+					case gol.StateChange:
+						states = append(states, e.NewState)
+					case gol.TurnComplete:
+						turn = e.CompletedTurns
+						// Press the keys once the steps have started.
+						if !pressed && states[len(states)-1] == gol.Paused {
+							pressed = true
+							for _, key := range test.keys {
+								keyPresses <- key
+							}
+						}
+					}
+				case <-timeout:
+					t.Fatalf("the keys were not handled while stepping, stuck at turn %v", turn)
+				}
+			}
+			if fmt.Sprint(states) != fmt.Sprint(test.expected) {
+				t.Errorf("expected states %v, got %v", test.expected, states)
+			}
+		})
+	}
+}
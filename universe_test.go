@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/gol"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// glider returns a 5x5 board with a glider in its top left corner, heading down and to the right.
+func glider() [][]byte {
+	board := make([][]byte, 5)
+	for y := range board {
+		board[y] = make([]byte, 5)
+	}
+	board[0][1], board[1][2], board[2][0], board[2][1], board[2][2] = 255, 255, 255, 255, 255
+	return board
+}
+
+// shifted returns the alive cells of a board moved by dx, dy.
+func shifted(board [][]byte, dx, dy int) []util.Cell {
+	var cells []util.Cell
+	for y := range board {
+		for x := range board[y] {
+			if board[y][x] == 255 {
+				cells = append(cells, util.Cell{X: x + dx, Y: y + dy})
+			}
+		}
+	}
+	return cells
+}
+
+// TestUnbounded checks that an unbounded board grows with its pattern instead of wrapping around,
+// that it evolves like a board large enough for its edges not to matter, and that it is output cropped.
+func TestUnbounded(t *testing.T) {
+	p := gol.Params{Threads: 4, Unbounded: true}
+
+	t.Run("glider", func(t *testing.T) {
+		events := make(chan gol.Event, 1000)
+		sim, err := gol.NewSimulation(gol.WithBoard(glider()), gol.WithParams(p), gol.WithEvents(events))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sim.Close()
+		if err := sim.Step(100); err != nil {
+			t.Fatal(err)
+		}
+		close(events)
+
+		expected := shifted(glider(), 25, 25)
+		assertEqualBoard(t, sim.AliveCells(), expected, gol.Params{ImageWidth: 30, ImageHeight: 30})
+		bounds := image.Rect(25, 25, 28, 28)
+		if sim.Bounds() != bounds {
+			t.Errorf("expected bounds %v, got %v", bounds, sim.Bounds())
+		}
+		if board := sim.Board(); len(board) != 3 || len(board[0]) != 3 {
+			t.Errorf("expected a 3x3 board, got %vx%v", len(board[0]), len(board))
+		}
+
+		var last gol.BoundsChanged
+		for event := range events {
+			if e, ok := event.(gol.BoundsChanged); ok {
+				last = e
+			}
+		}
+		if last.Bounds != bounds {
+			t.Errorf("expected the last BoundsChanged Event to be %v, got %v at turn %v", bounds, last.Bounds, last.CompletedTurns)
+		}
+	})
+
+	t.Run("reference", func(t *testing.T) {
+		const size, turns = 20, 30
+		r := rand.New(rand.NewSource(1))
+		soup := randomSoup(r, size, size)
+		// Nothing can travel more than a cell a turn, so a torus this large never wraps around.
+		padded := make([][]byte, size+2*(turns+1))
+		for y := range padded {
+			padded[y] = make([]byte, size+2*(turns+1))
+		}
+		for y := range soup {
+			copy(padded[y+turns+1][turns+1:], soup[y])
+		}
+		reference := referenceTurns(padded, turns)
+
+		sim, err := gol.NewSimulation(gol.WithBoard(soup), gol.WithParams(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sim.Close()
+		for turn := 0; turn <= turns; turn++ {
+			expected := shifted(reference[turn], -(turns + 1), -(turns + 1))
+			if !assertEqualBoard(t, sim.AliveCells(), expected, gol.Params{ImageWidth: len(padded), ImageHeight: len(padded)}) {
+				t.Fatalf("the board is wrong after %v turns", turn)
+			}
+			if err := sim.Step(1); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.Run("edits", func(t *testing.T) {
+		sim, err := gol.NewSimulation(gol.WithBoard(glider()), gol.WithParams(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sim.Close()
+		// A block far outside of the original board, which stays still.
+		block := []util.Cell{{X: -40, Y: -40}, {X: -39, Y: -40}, {X: -40, Y: -39}, {X: -39, Y: -39}}
+		if err := sim.Flip(block...); err != nil {
+			t.Fatal(err)
+		}
+		if bounds := image.Rect(-40, -40, 3, 3); sim.Bounds() != bounds {
+			t.Errorf("expected bounds %v, got %v", bounds, sim.Bounds())
+		}
+		if err := sim.Step(4); err != nil {
+			t.Fatal(err)
+		}
+		expected := append(block, shifted(glider(), 1, 1)...)
+		assertEqualBoard(t, sim.AliveCells(), expected, gol.Params{ImageWidth: 50, ImageHeight: 50})
+
+		if err := sim.Flip(expected...); err != nil {
+			t.Fatal(err)
+		}
+		if !sim.Bounds().Empty() || len(sim.AliveCells()) != 0 {
+			t.Errorf("expected every cell to be dead, got %v in %v", sim.AliveCells(), sim.Bounds())
+		}
+		if board := sim.Board(); len(board) != 1 || len(board[0]) != 1 || board[0][0] != 0 {
+			t.Errorf("expected a single dead cell, got %v", board)
+		}
+	})
+
+	t.Run("save", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "gol")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		sim, err := gol.NewSimulation(gol.WithBoard(glider()), gol.WithParams(p), gol.WithOutputDir(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sim.Close()
+		if err := sim.Step(4); err != nil {
+			t.Fatal(err)
+		}
+
+		path, err := sim.Save()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(path) != "3x3x4.pgm" {
+			t.Errorf("expected the image to be saved as 3x3x4.pgm, got %v", filepath.Base(path))
+		}
+		expected := shifted(glider(), 0, 0)
+		assertEqualBoard(t, util.ReadAliveCells(path, 3, 3), expected, gol.Params{ImageWidth: 3, ImageHeight: 3})
+
+		path, err = sim.SaveRLE()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rle := "#CXRLE Pos=1,1 Gen=4\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n"
+		if filepath.Base(path) != "3x3x4.rle" || string(data) != rle {
+			t.Errorf("expected 3x3x4.rle to contain\n%v\ngot %v containing\n%v", rle, filepath.Base(path), string(data))
+		}
+	})
+
+	t.Run("remote backend", func(t *testing.T) {
+		_, err := gol.NewSimulation(gol.WithBoard(glider()), gol.WithParams(p), gol.WithBackend(remoteBackend{}))
+		if !errors.Is(err, gol.ErrInvalidParams) {
+			t.Errorf("expected %v, got %v", gol.ErrInvalidParams, err)
+		}
+	})
+}
+
+// remoteBackend stands in for a Backend other than Local, which cannot evolve an unbounded board.
+type remoteBackend struct {
+	gol.Backend
+}
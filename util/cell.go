@@ -0,0 +1,44 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+// Cell is used as the return type for the testing framework.
+type Cell struct {
+	X, Y int
+}
+
+// ReadAliveCells returns the alive cells of the pgm image at path, panicking if it cannot be read or has a different size.
+func ReadAliveCells(path string, width, height int) []Cell {
+	data, ioError := ioutil.ReadFile(path)
+	Check(ioError)
+	cells, err := DecodeAliveCells(data, width, height)
+	Check(err)
+	return cells
+}
+
+// DecodeAliveCells returns the alive cells of a pgm image, which must have the given size.
+func DecodeAliveCells(data []byte, width, height int) ([]Cell, error) {
+	imageWidth, imageHeight, image, err := DecodePgm(data)
+	if err != nil {
+		return nil, err
+	}
+	if imageWidth != width || imageHeight != height {
+		return nil, fmt.Errorf("%w: size %vx%v, expected %vx%v", ErrBadPgm, imageWidth, imageHeight, width, height)
+	}
+
+	var cells []Cell
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if image[y*width+x] != 0 {
+				cells = append(cells, Cell{
+					X: x,
+					Y: y,
+				})
+			}
+		}
+	}
+	return cells, nil
+}
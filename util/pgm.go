@@ -0,0 +1,121 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrBadPgm is returned for data that is not a binary pgm image with a maxval of 255.
+var ErrBadPgm = errors.New("not a valid pgm image")
+
+// maxPgmField is the longest header field accepted, which holds any size that fits in memory,
+// so that reading a header without whitespace stops early instead of buffering the whole image.
+const maxPgmField = 10
+
+// DecodePgm decodes a binary (P5) pgm image with a maxval of 255, returning its size and its pixels row by row.
+// Comments starting with '#' are allowed between the header fields, and any bytes after the pixels are ignored.
+func DecodePgm(data []byte) (width, height int, pixels []byte, err error) {
+	fields, pixels := pgmHeader(data)
+	width, height, err = pgmSize(fields)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	// Dividing rather than multiplying keeps huge sizes in the header from overflowing.
+	if width > len(pixels)/height {
+		return 0, 0, nil, fmt.Errorf("%w: %v bytes of pixels for a %vx%v image", ErrBadPgm, len(pixels), width, height)
+	}
+	return width, height, pixels[:width*height], nil
+}
+
+// ReadPgmHeader reads the header of a binary pgm image like DecodePgm, leaving r at the start of its pixels,
+// so that images too large to hold in memory can be read row by row.
+func ReadPgmHeader(r io.ByteReader) (width, height int, err error) {
+	var fields []string
+	var field []byte
+	comment := false
+	for len(fields) < 4 {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			return 0, 0, fmt.Errorf("%w: missing P5 header", ErrBadPgm)
+		} else if err != nil {
+			return 0, 0, err
+		}
+		switch {
+		case comment:
+			comment = b != '\n' && b != '\r'
+		case isSpace(b) || b == '#':
+			// The byte that ends the last field is the one separating the header from the pixels.
+			if len(field) > 0 {
+				fields = append(fields, string(field))
+				field = nil
+			}
+			comment = b == '#'
+		default:
+			if len(field) == maxPgmField {
+				return 0, 0, fmt.Errorf("%w: header field %q... is too long", ErrBadPgm, field)
+			}
+			field = append(field, b)
+		}
+	}
+	return pgmSize(fields)
+}
+
+// pgmSize checks the header fields of a pgm image and returns its size.
+func pgmSize(fields []string) (width, height int, err error) {
+	if len(fields) < 4 || fields[0] != "P5" {
+		return 0, 0, fmt.Errorf("%w: missing P5 header", ErrBadPgm)
+	}
+	for _, field := range fields {
+		if len(field) > maxPgmField {
+			return 0, 0, fmt.Errorf("%w: header field %q is too long", ErrBadPgm, field)
+		}
+	}
+
+	width, err = strconv.Atoi(fields[1])
+	if err != nil || width <= 0 {
+		return 0, 0, fmt.Errorf("%w: width %q", ErrBadPgm, fields[1])
+	}
+	height, err = strconv.Atoi(fields[2])
+	if err != nil || height <= 0 {
+		return 0, 0, fmt.Errorf("%w: height %q", ErrBadPgm, fields[2])
+	}
+	if maxval, err := strconv.Atoi(fields[3]); err != nil || maxval != 255 {
+		return 0, 0, fmt.Errorf("%w: maxval %q, expected 255", ErrBadPgm, fields[3])
+	}
+	return width, height, nil
+}
+
+// pgmHeader splits the four header fields of a pgm image from its pixels.
+// The pixels start after the single whitespace character that follows the last field.
+func pgmHeader(data []byte) ([]string, []byte) {
+	var fields []string
+	i := 0
+	for len(fields) < 4 && i < len(data) {
+		if isSpace(data[i]) {
+			i++
+			continue
+		}
+		if data[i] == '#' {
+			for i < len(data) && data[i] != '\n' && data[i] != '\r' {
+				i++
+			}
+			continue
+		}
+		start := i
+		for i < len(data) && !isSpace(data[i]) && data[i] != '#' {
+			i++
+		}
+		fields = append(fields, string(data[start:i]))
+	}
+	if i < len(data) {
+		i++
+	}
+	return fields, data[i:]
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+}
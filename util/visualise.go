@@ -106,3 +106,33 @@ func squaresToStrings(given, expected [][]uint8, width, height int) []string {
 
 	return output
 }
+
+// WindowToString shows the cells of both worlds within radius of x, y side by side,
+// for comparing boards that are too big to print in full.
+func WindowToString(given, expected [][]uint8, x, y, radius int) string {
+	height := len(expected)
+	width := 0
+	if height > 0 {
+		width = len(expected[0])
+	}
+	clamp := func(v, size int) int {
+		if v < 0 {
+			return 0
+		}
+		if v > size {
+			return size
+		}
+		return v
+	}
+	startX, endX := clamp(x-radius, width), clamp(x+radius+1, width)
+	startY, endY := clamp(y-radius, height), clamp(y+radius+1, height)
+	crop := func(world [][]uint8) [][]uint8 {
+		window := make([][]uint8, endY-startY)
+		for i := range window {
+			window[i] = world[startY+i][startX:endX]
+		}
+		return window
+	}
+	output := fmt.Sprintf("  Columns %v to %v and rows %v to %v, with row %v shown as 0:\n", startX, endX-1, startY, endY-1, startY)
+	return output + matricesToString(crop(given), crop(expected), endX-startX, endY-startY)
+}